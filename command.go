@@ -0,0 +1,213 @@
+package greedyflag
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// --- Command Tree ---
+
+// Command is a node in a tree of subcommands (e.g. "mytool index", "mytool serve").
+// Each command owns a FlagSet with its own flags, greedy flags and positional mode,
+// plus a set of persistent flags that are inherited by all of its descendants.
+//
+// Subcommand names are matched only at the start of the argument list, before any
+// flags: "mytool index -e go py" dispatches to "index", but in "mytool -v index"
+// the token "index" is treated as an argument of "mytool". This keeps greedy flags
+// from ever competing with command names for the same token.
+type Command struct {
+	Name  string // Name used to invoke the command.
+	Short string // One-line description shown in the parent's command list.
+	// Run is called with the command and its positional arguments after a
+	// successful parse. Commands without Run only dispatch to subcommands.
+	Run func(cmd *Command, args []string) error
+
+	parent          *Command
+	commands        []*Command
+	flags           *FlagSet // Local flags and positional configuration
+	persistentFlags *FlagSet // Flags inherited by descendants
+}
+
+// NewCommand returns a command with the given name, one-line description and run
+// function. run may be nil for commands that only group subcommands.
+func NewCommand(name string, short string, run func(cmd *Command, args []string) error) *Command {
+	c := &Command{
+		Name:            name,
+		Short:           short,
+		Run:             run,
+		flags:           NewFlagSet(name),
+		persistentFlags: NewFlagSet(name),
+	}
+	c.flags.cmd = c
+	return c
+}
+
+// AddCommand adds one or more subcommands to c.
+func (c *Command) AddCommand(cmds ...*Command) {
+	for _, sub := range cmds {
+		if sub == c {
+			panic("greedyflag: command cannot be a child of itself")
+		}
+		if c.findChild(sub.Name) != nil {
+			panic(fmt.Sprintf("greedyflag: command redefined: %s", sub.Name))
+		}
+		sub.parent = c
+		c.commands = append(c.commands, sub)
+	}
+}
+
+// Commands returns the direct subcommands of c in the order they were added.
+func (c *Command) Commands() []*Command {
+	return c.commands
+}
+
+// Parent returns the parent command, or nil for the root of the tree.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// Flags returns the command's local flag set. Positional argument modes
+// (AllowArbitraryLeadingPositionals, SetMandatoryNArgs) are configured on it.
+func (c *Command) Flags() *FlagSet {
+	return c.flags
+}
+
+// PersistentFlags returns the set of flags that apply to c and all of its
+// descendants. Positional configuration on this set is ignored.
+func (c *Command) PersistentFlags() *FlagSet {
+	return c.persistentFlags
+}
+
+// CommandPath returns the space-separated names from the root to c (e.g. "mytool index").
+func (c *Command) CommandPath() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.CommandPath() + " " + c.Name
+}
+
+// findChild returns the direct subcommand with the given name, or nil.
+func (c *Command) findChild(name string) *Command {
+	for _, sub := range c.commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Execute parses os.Args[1:] and runs the selected command. See ExecuteArgs.
+func (c *Command) Execute() error {
+	return c.execute(os.Args[1:], 1)
+}
+
+// ExecuteArgs walks the leading subcommand names in arguments, parses the rest with the
+// selected command's flags (including persistent flags of the command and its ancestors)
+// and calls its Run function with the positional arguments.
+// If help was requested, the selected command's usage is printed and ErrHelp is returned.
+// If the arguments do not parse and one of them names a subcommand (as in "mytool -v serve"),
+// the error says that subcommands must come before flags.
+func (c *Command) ExecuteArgs(arguments []string) error {
+	return c.execute(arguments, 0)
+}
+
+// execute implements ExecuteArgs; argOffset is the index of arguments[0] in the full command
+// line, so value sources report positions in os.Args.
+func (c *Command) execute(arguments []string, argOffset int) error {
+	cmd := c
+	for len(arguments) > 0 {
+		sub := cmd.findChild(arguments[0])
+		if sub == nil {
+			break
+		}
+		cmd = sub
+		arguments = arguments[1:]
+		argOffset++
+	}
+	slog.Debug("Dispatching to command", "command", cmd.CommandPath(), "args", arguments)
+
+	if cmd.Run == nil && len(cmd.commands) > 0 && len(arguments) > 0 && !strings.HasPrefix(arguments[0], "-") {
+		return fmt.Errorf("%w: unknown command %q for %q", ErrParsing, arguments[0], cmd.CommandPath())
+	}
+
+	fs := cmd.flags
+	for p := cmd; p != nil; p = p.parent {
+		p.persistentFlags.VisitAll(fs.addInheritedFlag)
+		fs.inheritConfigFlag(p.persistentFlags)
+		fs.inheritEnvSettings(p.persistentFlags)
+		if p != cmd {
+			fs.inheritEnvSettings(p.flags)
+		}
+	}
+
+	fs.argOffset = argOffset
+	if err := fs.ParseArgs(arguments); err != nil {
+		if errors.Is(err, ErrHelp) {
+			fs.Usage()
+			return err
+		}
+		// Subcommand names are only matched before flags; say so rather than reporting the name
+		for _, arg := range arguments {
+			if cmd.findChild(arg) != nil {
+				return fmt.Errorf("%w: subcommand %q must come before flags", ErrParsing, arg)
+			}
+		}
+		return err
+	}
+	if cmd.Run == nil {
+		if len(cmd.commands) > 0 {
+			return fmt.Errorf("%w: %q requires a subcommand", ErrValidation, cmd.CommandPath())
+		}
+		return nil
+	}
+	return cmd.Run(cmd, fs.Args())
+}
+
+// addInheritedFlag adds a persistent flag of an ancestor to the set.
+// A local flag with the same long name shadows the inherited one.
+func (fs *FlagSet) addInheritedFlag(f *Flag) {
+	if _, exists := fs.flags[f.Name]; exists {
+		slog.Debug("Persistent flag shadowed by local flag", "flag", f.Name)
+		return
+	}
+	if neg := fs.negatedFlag(f.Name); neg != nil {
+		panic(fmt.Sprintf("greedyflag: persistent flag redefined: %s (negation of --%s)", f.Name, neg.Name))
+	}
+	if _, exists := fs.flags["no-"+f.Name]; f.Negatable && exists {
+		panic(fmt.Sprintf("greedyflag: persistent flag redefined: --%s is negatable but --no-%s is defined", f.Name, f.Name))
+	}
+	if r := fs.remainderFlag(); f.IsRemainder && r != nil {
+		panic(fmt.Sprintf("greedyflag: only one remainder flag allowed per set: --%s and --%s", r.Name, f.Name))
+	}
+	if f.Shorthand != "" {
+		shorthandRune, _ := utf8.DecodeRuneInString(f.Shorthand)
+		if _, exists := fs.shortFlags[shorthandRune]; exists {
+			panic(fmt.Sprintf("greedyflag: persistent flag shorthand redefined: -%s", f.Shorthand))
+		}
+		fs.shortFlags[shorthandRune] = f
+	}
+	fs.flags[f.Name] = f
+}
+
+// printCommands prints the list of subcommands for the command owning the set.
+func (fs *FlagSet) printCommands() {
+	if fs.cmd == nil || len(fs.cmd.commands) == 0 {
+		return
+	}
+	out := fs.Output()
+	fmt.Fprintf(out, "\nCommands:\n")
+	for _, sub := range fs.cmd.commands {
+		line := "  " + sub.Name
+		if len(line) < 24 {
+			line += strings.Repeat(" ", 24-len(line))
+		} else {
+			line += "\n    \t"
+		}
+		fmt.Fprintln(out, line+sub.Short)
+	}
+	fmt.Fprintf(out, "\nUse \"%s <command> --help\" for more information about a command.\n", fs.cmd.CommandPath())
+}
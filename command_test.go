@@ -0,0 +1,140 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testTree is a "mytool" command with "index" and "serve" subcommands that record their Run calls.
+type testTree struct {
+	root, index, serve *Command
+	ran                string
+	args               []string
+	verbose            *bool
+	exts               *[]string
+	out                *strings.Builder
+}
+
+func newTestTree(t *testing.T) *testTree {
+	t.Helper()
+	tr := &testTree{out: &strings.Builder{}}
+	run := func(cmd *Command, args []string) error {
+		tr.ran, tr.args = cmd.CommandPath(), args
+		return nil
+	}
+	tr.root = NewCommand("mytool", "", nil)
+	tr.root.Flags().SetOutput(tr.out)
+	tr.verbose = tr.root.PersistentFlags().BoolP("verbose", "v", false, "Verbose")
+	tr.index = NewCommand("index", "Index files", run)
+	tr.index.Flags().SetOutput(tr.out)
+	if err := tr.index.Flags().AllowArbitraryTrailingPositionals(); err != nil {
+		t.Fatal(err)
+	}
+	tr.exts = tr.index.Flags().StringSliceGreedyP("extensions", "e", nil, "Extensions")
+	tr.serve = NewCommand("serve", "Serve the index", run)
+	tr.serve.Flags().SetOutput(tr.out)
+	tr.root.AddCommand(tr.index, tr.serve)
+	return tr
+}
+
+func TestCommandDispatch(t *testing.T) {
+	tr := newTestTree(t)
+	if err := tr.root.ExecuteArgs([]string{"index", "-v", "-e", "go", "py", "--", "src", "docs"}); err != nil {
+		t.Fatal(err)
+	}
+	if tr.ran != "mytool index" || !reflect.DeepEqual(tr.args, []string{"src", "docs"}) {
+		t.Errorf("ran %q with %q, want mytool index with [src docs]", tr.ran, tr.args)
+	}
+	if !*tr.verbose || !reflect.DeepEqual(*tr.exts, []string{"go", "py"}) {
+		t.Errorf("verbose = %v, extensions = %q; want true, [go py]", *tr.verbose, *tr.exts)
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		wantMsg string
+	}{
+		{"requires a subcommand", nil, ErrValidation, `"mytool" requires a subcommand`},
+		{"unknown command", []string{"stats"}, ErrParsing, `unknown command "stats" for "mytool"`},
+		{"subcommand after a flag", []string{"-v", "serve"}, ErrParsing, `subcommand "serve" must come before flags`},
+		{"unknown flag in subcommand", []string{"serve", "--nope"}, ErrParsing, "--nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTestTree(t)
+			err := tr.root.ExecuteArgs(tt.args)
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ExecuteArgs(%q) error = %v, want %v containing %q", tt.args, err, tt.wantErr, tt.wantMsg)
+			}
+			if tr.ran != "" {
+				t.Errorf("ran %q after an error", tr.ran)
+			}
+		})
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	tr := newTestTree(t)
+	if err := tr.root.ExecuteArgs([]string{"--help"}); !errors.Is(err, ErrHelp) {
+		t.Fatalf("ExecuteArgs error = %v, want ErrHelp", err)
+	}
+	for _, want := range []string{
+		"Usage: mytool <command> [args...]",
+		"\nCommands:\n  index                 Index files\n  serve                 Serve the index\n",
+		`Use "mytool <command> --help" for more information about a command.`,
+		"--verbose",
+	} {
+		if !strings.Contains(tr.out.String(), want) {
+			t.Errorf("usage %q does not contain %q", tr.out.String(), want)
+		}
+	}
+
+	tr = newTestTree(t)
+	if err := tr.root.ExecuteArgs([]string{"index", "-h"}); !errors.Is(err, ErrHelp) {
+		t.Fatalf("ExecuteArgs error = %v, want ErrHelp", err)
+	}
+	if !strings.HasPrefix(tr.out.String(), "Usage: mytool index [flags] [args...]\n") {
+		t.Errorf("subcommand usage = %q", tr.out.String())
+	}
+}
+
+func TestCommandInheritedNegationConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(root, serve *Command)
+	}{
+		{"inherited no- flag", func(root, serve *Command) {
+			serve.Flags().BoolP("cache", "", true, "Cache")
+			if err := serve.Flags().SetNegatable("cache"); err != nil {
+				t.Fatal(err)
+			}
+			root.PersistentFlags().BoolP("no-cache", "", false, "No cache")
+		}},
+		{"inherited negatable flag", func(root, serve *Command) {
+			serve.Flags().BoolP("no-cache", "", false, "No cache")
+			root.PersistentFlags().BoolP("cache", "", true, "Cache")
+			if err := root.PersistentFlags().SetNegatable("cache"); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewCommand("mytool", "", nil)
+			serve := NewCommand("serve", "", func(*Command, []string) error { return nil })
+			root.AddCommand(serve)
+			tt.setup(root, serve)
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(r.(string), "persistent flag redefined") {
+					t.Errorf("recover() = %v, want a persistent flag redefined panic", r)
+				}
+			}()
+			root.ExecuteArgs([]string{"serve"})
+		})
+	}
+}
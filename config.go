@@ -0,0 +1,520 @@
+package greedyflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// --- Config File Loading ---
+
+// UnknownKeyPolicy controls how a config file key that matches no flag is treated.
+type UnknownKeyPolicy int
+
+const (
+	// UnknownKeyError reports an unknown key as a parse error (the default).
+	UnknownKeyError UnknownKeyPolicy = iota
+	// UnknownKeyWarn prints a warning to the set's output and ignores the key.
+	UnknownKeyWarn
+)
+
+// configEntry is one key of a config file with its values, in file order.
+type configEntry struct {
+	key    string
+	values []string // Passed to Value.Set one by one; "k=v" for nested tables/objects
+	line   int      // 1-based line of the key in the file
+}
+
+// ConfigFileVarP defines a flag with specified name, shorthand, default value, and usage string
+// whose value is the path of a config file to load flag values from. The file is read after the
+// command line and environment, so values given there take precedence; keys are long flag names.
+// Supported formats, chosen by extension, are JSON (.json), a YAML subset (.yaml, .yml), a TOML
+// subset (.toml) and INI (.ini, .conf, .cfg). List values feed greedy flags one element at a
+// time; nested objects, YAML mappings and TOML/INI sections feed map flags as key=value pairs.
+// A missing file is only an error if the path was given explicitly. At most one config file
+// flag may be defined per set. Defined on a command's persistent flags, it also loads the file for
+// every subcommand, using the unknown key policy of the defining set unless the subcommand's set
+// chooses UnknownKeyWarn. The argument p points to a string variable in which to store the path.
+func (fs *FlagSet) ConfigFileVarP(p *string, name string, shorthand string, value string, usage string) {
+	if fs.configFlag != nil {
+		panic(fmt.Sprintf("greedyflag: only one config file flag allowed per set: --%s and --%s", fs.configFlag.Name, name))
+	}
+	fs.StringVarP(p, name, shorthand, value, usage)
+	fs.configFlag = fs.flags[name]
+}
+
+// ConfigFileVarP defines a config file flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a string variable in which to store the path. See FlagSet.ConfigFileVarP.
+func ConfigFileVarP(p *string, name string, shorthand string, value string, usage string) {
+	CommandLine.ConfigFileVarP(p, name, shorthand, value, usage)
+}
+
+// ConfigFileP is like ConfigFileVarP, but returns a pointer to a string variable.
+func (fs *FlagSet) ConfigFileP(name string, shorthand string, value string, usage string) *string {
+	p := new(string)
+	fs.ConfigFileVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// ConfigFileP is like ConfigFileVarP, but returns a pointer to a string variable.
+func ConfigFileP(name string, shorthand string, value string, usage string) *string {
+	return CommandLine.ConfigFileP(name, shorthand, value, usage)
+}
+
+// SetUnknownConfigKeyPolicy sets how config file keys that match no flag are treated.
+func (fs *FlagSet) SetUnknownConfigKeyPolicy(policy UnknownKeyPolicy) {
+	fs.unknownKeyPolicy = policy
+}
+
+// SetUnknownConfigKeyPolicy sets how config file keys that match no flag of the default set are treated.
+func SetUnknownConfigKeyPolicy(policy UnknownKeyPolicy) {
+	CommandLine.SetUnknownConfigKeyPolicy(policy)
+}
+
+// inheritConfigFlag adopts the config file flag of from (the persistent set of an ancestor
+// command) if fs has none of its own and the flag was merged into fs rather than shadowed.
+func (fs *FlagSet) inheritConfigFlag(from *FlagSet) {
+	if fs.configFlag != nil || from.configFlag == nil || fs.flags[from.configFlag.Name] != from.configFlag {
+		return
+	}
+	fs.configFlag = from.configFlag
+	if fs.unknownKeyPolicy == UnknownKeyError {
+		fs.unknownKeyPolicy = from.unknownKeyPolicy
+	}
+}
+
+// applyConfig sets flags not given on the command line or in the environment from the
+// config file named by the config file flag, if any.
+func (fs *FlagSet) applyConfig() error {
+	if fs.configFlag == nil {
+		return nil
+	}
+	path := fs.configFlag.Value.String()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !fs.configFlag.changed {
+			slog.Debug("Default config file not found", "path", path)
+			return nil
+		}
+		return fmt.Errorf("%w: reading config file: %v", ErrParsing, err)
+	}
+	entries, err := parseConfig(path, data)
+	if err != nil {
+		return err
+	}
+
+	fromFile := map[*Flag]bool{} // Flags set by earlier entries, which later ones may add to
+	for _, e := range entries {
+		f := fs.Lookup(e.key)
+		if f == nil || f == fs.configFlag || e.key == "help" {
+			if fs.unknownKeyPolicy == UnknownKeyWarn {
+				fmt.Fprintf(fs.Output(), "Warning: %s:%d: unknown config key %q\n", path, e.line, e.key)
+				continue
+			}
+			return fmt.Errorf("%w: %s:%d: unknown config key %q", ErrParsing, path, e.line, e.key)
+		}
+		if f.changed && !fromFile[f] {
+			slog.Debug("Config value overridden", "flag", f.Name, "path", path, "line", e.line)
+			continue
+		}
+		if len(e.values) == 0 {
+			// A null or empty value leaves the flag as it is
+			slog.Debug("Config key without a value ignored", "flag", f.Name, "path", path, "line", e.line)
+			continue
+		}
+		if !fromFile[f] {
+			resetValue(f.Value) // The file replaces the default rather than adding to it
+		}
+		for _, v := range e.values {
+			if err := f.Value.Set(v); err != nil {
+				return fmt.Errorf("%w: %s:%d: invalid value %q for flag --%s: %v", ErrParsing, path, e.line, v, f.Name, err)
+			}
+		}
+		f.changed = true
+		f.Source = ValueSource{Kind: SourceConfigFile, File: path, Line: e.line}
+		fromFile[f] = true
+		slog.Debug("Flag set from config file", "flag", f.Name, "path", path, "line", e.line, "values", e.values)
+	}
+	return nil
+}
+
+// parseConfig parses a config file in the format indicated by the extension of path.
+func parseConfig(path string, data []byte) ([]configEntry, error) {
+	var entries []configEntry
+	var line int
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		entries, line, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		entries, line, err = parseYAMLConfig(data)
+	case ".toml":
+		entries, line, err = parseTOMLConfig(data)
+	case ".ini", ".conf", ".cfg":
+		entries, line, err = parseINIConfig(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported config file format %q (want .json, .yaml, .toml or .ini)", ErrParsing, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s:%d: %v", ErrParsing, path, line, err)
+	}
+	return entries, nil
+}
+
+// parseJSONConfig parses a JSON object. Values may be scalars, arrays of scalars, or objects
+// of scalars. The returned line is where an error occurred.
+func parseJSONConfig(data []byte) ([]configEntry, int, error) {
+	lineAt := func(offset int64) int { return bytes.Count(data[:offset], []byte("\n")) + 1 }
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, lineAt(dec.InputOffset()), errors.New("config must be a JSON object")
+	}
+	var entries []configEntry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, lineAt(dec.InputOffset()), err
+		}
+		key := tok.(string) // Object keys are always strings
+		line := lineAt(dec.InputOffset())
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, line, err
+		}
+		values, err := jsonValues(v)
+		if err != nil {
+			return nil, line, fmt.Errorf("key %q: %v", key, err)
+		}
+		entries = append(entries, configEntry{key: key, values: values, line: line})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, lineAt(dec.InputOffset()), err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, lineAt(dec.InputOffset()), errors.New("unexpected data after the JSON object")
+	}
+	return entries, 0, nil
+}
+
+// jsonValues flattens a decoded JSON value into the strings passed to Value.Set.
+func jsonValues(v any) ([]string, error) {
+	switch v := v.(type) {
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			s, err := jsonScalar(elem)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	case map[string]any:
+		keys := mapsKeys(v) // Sorted
+		values := make([]string, 0, len(v))
+		for _, k := range keys {
+			s, err := jsonScalar(v[k])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, k+"="+s)
+		}
+		return values, nil
+	case nil:
+		return nil, nil
+	}
+	s, err := jsonScalar(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+// jsonScalar renders a JSON string, number or boolean.
+func jsonScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("nested value %v is not supported", v)
+}
+
+// parseYAMLConfig parses a subset of YAML: top-level "key: value" pairs, where the value may be
+// a scalar, a flow list ("[a, b]"), or an indented block of "- item" list entries or
+// "name: value" mapping entries. Comments start with a '#' at the start of a line or after
+// whitespace, so "url: http://host/a#frag" keeps its fragment.
+func parseYAMLConfig(data []byte) ([]configEntry, int, error) {
+	var entries []configEntry
+	var block *configEntry // Top-level key whose value is an indented block
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if block == nil {
+				return nil, lineNo, errors.New("unexpected indentation")
+			}
+			if item, ok := strings.CutPrefix(trimmed, "-"); ok {
+				block.values = append(block.values, unquoteConfig(strings.TrimSpace(item)))
+			} else if k, v, ok := strings.Cut(trimmed, ":"); ok {
+				block.values = append(block.values, strings.TrimSpace(k)+"="+unquoteConfig(strings.TrimSpace(v)))
+			} else {
+				return nil, lineNo, fmt.Errorf("expected \"- item\" or \"name: value\", got %q", trimmed)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, lineNo, fmt.Errorf("expected \"key: value\", got %q", trimmed)
+		}
+		entries = append(entries, configEntry{key: strings.TrimSpace(key), line: lineNo})
+		block = nil
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			block = &entries[len(entries)-1]
+		case strings.HasPrefix(value, "["):
+			items, err := parseConfigList(value)
+			if err != nil {
+				return nil, lineNo, err
+			}
+			entries[len(entries)-1].values = items
+		default:
+			entries[len(entries)-1].values = []string{unquoteConfig(value)}
+		}
+	}
+	return entries, 0, nil
+}
+
+// parseTOMLConfig parses a subset of TOML: "key = value" pairs with string, number, boolean or
+// array values (arrays may span lines), and [table] headers whose keys become key=value pairs
+// of the table's flag. Comments start with '#'.
+func parseTOMLConfig(data []byte) ([]configEntry, int, error) {
+	var entries []configEntry
+	table := -1 // Index of the entry for the current [table], or -1 at top level
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripComment(lines[i], "#"))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, lineNo, fmt.Errorf("unsupported table header %q", line)
+			}
+			entries = append(entries, configEntry{key: strings.TrimSpace(line[1 : len(line)-1]), line: lineNo})
+			table = len(entries) - 1
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, lineNo, fmt.Errorf("expected \"key = value\", got %q", line)
+		}
+		key = unquoteConfig(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		var values []string
+		if strings.HasPrefix(value, "[") {
+			// Join continuation lines until the array is closed
+			for !listClosed(value) && i+1 < len(lines) {
+				i++
+				value += " " + strings.TrimSpace(stripComment(lines[i], "#"))
+			}
+			items, err := parseConfigList(value)
+			if err != nil {
+				return nil, lineNo, err
+			}
+			values = items
+		} else {
+			values = []string{unquoteConfig(value)}
+		}
+		if table >= 0 {
+			for _, v := range values {
+				entries[table].values = append(entries[table].values, key+"="+v)
+			}
+			continue
+		}
+		entries = append(entries, configEntry{key: key, values: values, line: lineNo})
+	}
+	return entries, 0, nil
+}
+
+// parseINIConfig parses INI: "key = value" or "key: value" pairs and [section] headers whose
+// keys become key=value pairs of the section's flag. A key given more than once yields one
+// entry per occurrence, so repeated keys feed greedy flags. Comments start with ';' or '#'.
+func parseINIConfig(data []byte) ([]configEntry, int, error) {
+	var entries []configEntry
+	section := -1 // Index of the entry for the current [section], or -1 at top level
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, lineNo, fmt.Errorf("malformed section header %q", line)
+			}
+			entries = append(entries, configEntry{key: strings.TrimSpace(line[1 : len(line)-1]), line: lineNo})
+			section = len(entries) - 1
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, lineNo, fmt.Errorf("expected \"key = value\", got %q", line)
+		}
+		key := strings.TrimSpace(line[:sep])
+		value := unquoteConfig(strings.TrimSpace(line[sep+1:]))
+		if section >= 0 {
+			entries[section].values = append(entries[section].values, key+"="+value)
+			continue
+		}
+		entries = append(entries, configEntry{key: key, values: []string{value}, line: lineNo})
+	}
+	return entries, 0, nil
+}
+
+// stripComment removes a trailing comment starting with marker outside of quotes.
+func stripComment(line string, marker string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case strings.HasPrefix(line[i:], marker):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// stripYAMLComment removes a trailing YAML comment: a '#' outside of quotes that starts the line
+// or follows whitespace. Quotes only count at the start of a scalar, so the apostrophe in
+// "name: don't # c" does not hide the comment.
+func stripYAMLComment(line string) string {
+	var quote byte
+	scalarStart := true // A quote here opens a quoted scalar
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"':
+			if c == '\\' {
+				i++ // Skip the escaped character
+			} else if c == '"' {
+				quote = 0
+			}
+			continue
+		case quote == '\'':
+			if c == '\'' && i+1 < len(line) && line[i+1] == '\'' {
+				i++ // '' is an escaped single quote
+			} else if c == '\'' {
+				quote = 0
+			}
+			continue
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		case scalarStart && (c == '"' || c == '\''):
+			quote = c
+			scalarStart = false
+			continue
+		}
+		switch c {
+		case ' ', '\t':
+			if i > 0 && strings.IndexByte(":-,", line[i-1]) >= 0 {
+				scalarStart = true
+			}
+		case '[', ',':
+			scalarStart = true
+		default:
+			scalarStart = false
+		}
+	}
+	return line
+}
+
+// listClosed reports whether the '[' list in s has its closing ']' outside of quotes.
+func listClosed(s string) bool {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+// parseConfigList splits a one-level "[a, 'b, c', 3]" list into unquoted items.
+func parseConfigList(s string) ([]string, error) {
+	if !strings.HasSuffix(s, "]") || !listClosed(s) {
+		return nil, fmt.Errorf("unterminated list %q", s)
+	}
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	var items []string
+	var quote rune
+	start := 0
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			return nil, fmt.Errorf("nested list in %q is not supported", s)
+		case r == ',':
+			items = append(items, unquoteConfig(strings.TrimSpace(body[start:i])))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(body[start:]); last != "" { // Allow a trailing comma
+		items = append(items, unquoteConfig(last))
+	}
+	return items, nil
+}
+
+// unquoteConfig removes double quotes (with escapes) or single quotes (literal) around s.
+func unquoteConfig(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
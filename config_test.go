@@ -0,0 +1,271 @@
+package greedyflag
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a file with the given name in a temporary directory.
+func writeConfig(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFileInheritedBySubcommands(t *testing.T) {
+	path := writeConfig(t, "app.json", `{"out": "/tmp/file", "port": 8080}`)
+
+	root := NewCommand("mytool", "", nil)
+	root.PersistentFlags().ConfigFileP("config", "c", "", "Config file")
+	out := root.PersistentFlags().StringP("out", "o", "", "Output")
+	var port int
+	serve := NewCommand("serve", "Serve", func(*Command, []string) error { return nil })
+	serve.Flags().IntVarP(&port, "port", "p", 80, "Port")
+	root.AddCommand(serve)
+
+	if err := root.ExecuteArgs([]string{"serve", "--config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if *out != "/tmp/file" || port != 8080 {
+		t.Errorf("out = %q, port = %d; want /tmp/file, 8080", *out, port)
+	}
+}
+
+// configFlags holds the values of the flags defined by newConfigSet.
+type configFlags struct {
+	Name    string
+	Port    int
+	Verbose bool
+	Tags    []string
+	Labels  map[string]string
+}
+
+// newConfigSet returns a set with a --config flag and one flag of each config value shape.
+func newConfigSet(t *testing.T) (*FlagSet, *configFlags) {
+	t.Helper()
+	fs := NewFlagSet("mytool")
+	fs.SetOutput(&strings.Builder{})
+	v := &configFlags{}
+	fs.ConfigFileP("config", "c", "", "Config file")
+	fs.StringVarP(&v.Name, "name", "n", "default", "Name")
+	fs.IntVarP(&v.Port, "port", "p", 80, "Port")
+	fs.BoolVarP(&v.Verbose, "verbose", "v", false, "Verbose")
+	fs.StringSliceGreedyVarP(&v.Tags, "tags", "t", nil, "Tags")
+	fs.StringToStringGreedyVarP(&v.Labels, "labels", "l", nil, "Labels")
+	return fs, v
+}
+
+func TestConfigFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		args    []string
+		want    configFlags
+	}{
+		{
+			name:    "json",
+			file:    "app.json",
+			content: `{"name": "svc", "port": 8080, "verbose": true, "tags": ["a", "b"], "labels": {"env": "prod"}}`,
+			want:    configFlags{Name: "svc", Port: 8080, Verbose: true, Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name: "yaml block lists and mappings",
+			file: "app.yaml",
+			content: `# comment line
+name: svc  # trailing comment
+port: 8080
+verbose: true
+tags:
+  - a
+  - "b # c"
+labels:
+  env: prod
+  tier: 'web'
+`,
+			want: configFlags{Name: "svc", Port: 8080, Verbose: true, Tags: []string{"a", "b # c"}, Labels: map[string]string{"env": "prod", "tier": "web"}},
+		},
+		{
+			name:    "yaml flow list",
+			file:    "app.yml",
+			content: "tags: [a, 'b, c', \"d\"]\n",
+			want:    configFlags{Name: "default", Port: 80, Tags: []string{"a", "b, c", "d"}},
+		},
+		{
+			name:    "yaml hash without whitespace is not a comment",
+			file:    "app.yaml",
+			content: "name: http://example.com/a#frag\n",
+			want:    configFlags{Name: "http://example.com/a#frag", Port: 80},
+		},
+		{
+			name:    "yaml apostrophe inside a plain scalar",
+			file:    "app.yaml",
+			content: "name: don't # comment\n",
+			want:    configFlags{Name: "don't", Port: 80},
+		},
+		{
+			name: "toml tables and multi-line arrays",
+			file: "app.toml",
+			content: `name = "svc # not a comment" # comment
+port = 8080
+verbose = true
+tags = [
+  "a", # first
+  'b',
+]
+
+[labels]
+env = "prod"
+tier = "web"
+`,
+			want: configFlags{Name: "svc # not a comment", Port: 8080, Verbose: true, Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod", "tier": "web"}},
+		},
+		{
+			name: "ini sections and repeated keys",
+			file: "app.ini",
+			content: `; comment
+# comment
+name = "svc"
+port: 8080
+tags = a
+tags = b
+
+[labels]
+env = prod
+`,
+			want: configFlags{Name: "svc", Port: 8080, Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name:    "command line overrides the file",
+			file:    "app.toml",
+			content: "name = \"svc\"\nport = 8080\ntags = [\"a\", \"b\"]\n",
+			args:    []string{"--name", "cli", "-t", "x"},
+			want:    configFlags{Name: "cli", Port: 8080, Tags: []string{"x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, got := newConfigSet(t)
+			path := writeConfig(t, tt.file, tt.content)
+			if err := fs.ParseArgs(append([]string{"--config", path}, tt.args...)); err != nil {
+				t.Fatal(err)
+			}
+			if g, w := fmt.Sprintf("%+v", *got), fmt.Sprintf("%+v", tt.want); g != w {
+				t.Errorf("values = %s, want %s", g, w)
+			}
+			if src := fs.Lookup("port").Source; tt.want.Port != 80 && (src.Kind != SourceConfigFile || src.File != path || src.Line == 0) {
+				t.Errorf("port source = %+v, want %s with a line", src, path)
+			}
+		})
+	}
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"unknown key", "app.json", `{"nope": 1}`},
+		{"invalid value", "app.yaml", "port: high\n"},
+		{"malformed yaml", "app.yaml", "  - a\n"},
+		{"unterminated toml array", "app.toml", "tags = [\"a\",\n"},
+		{"malformed ini section", "app.ini", "[labels\n"},
+		{"unsupported extension", "app.xml", "<name>svc</name>"},
+		{"json trailing data", "app.json", `{"name": "x"} garbage`},
+		{"json second object", "app.json", `{"name": "x"} {"port": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _ := newConfigSet(t)
+			path := writeConfig(t, tt.file, tt.content)
+			if err := fs.ParseArgs([]string{"--config", path}); !errors.Is(err, ErrParsing) {
+				t.Fatalf("ParseArgs error = %v, want ErrParsing", err)
+			}
+		})
+	}
+}
+
+func TestConfigFileUnknownKeyWarning(t *testing.T) {
+	fs, got := newConfigSet(t)
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.SetUnknownConfigKeyPolicy(UnknownKeyWarn)
+	path := writeConfig(t, "app.ini", "nope = 1\nport = 8080\n")
+	if err := fs.ParseArgs([]string{"--config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Port != 8080 {
+		t.Errorf("port = %d, want 8080", got.Port)
+	}
+	if want := fmt.Sprintf("Warning: %s:1: unknown config key \"nope\"", path); !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want it to contain %q", out.String(), want)
+	}
+}
+
+func TestConfigFileMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	// A missing default file is ignored
+	fs := NewFlagSet("mytool")
+	fs.ConfigFileP("config", "c", missing, "Config file")
+	if err := fs.ParseArgs(nil); err != nil {
+		t.Fatalf("ParseArgs with missing default file: %v", err)
+	}
+
+	// A missing file given explicitly is an error
+	fs = NewFlagSet("mytool")
+	fs.ConfigFileP("config", "c", "", "Config file")
+	if err := fs.ParseArgs([]string{"--config", missing}); !errors.Is(err, ErrParsing) {
+		t.Fatalf("ParseArgs error = %v, want ErrParsing", err)
+	}
+}
+
+func TestConfigFileReplacesDefaults(t *testing.T) {
+	fs := NewFlagSet("mytool")
+	fs.ConfigFileP("config", "c", "", "Config file")
+	tags := fs.StringSliceGreedyP("tags", "t", []string{"d"}, "Tags")
+	labels := fs.StringToStringGreedyP("labels", "l", map[string]string{"a": "1"}, "Labels")
+	ports := fs.IntSliceGreedyP("ports", "p", []int{80}, "Ports")
+	path := writeConfig(t, "app.ini", "tags = a\ntags = b\nports = 443\n[labels]\nb = 2\n")
+	if err := fs.ParseArgs([]string{"--config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if g, w := fmt.Sprint(*tags, *labels, *ports), fmt.Sprint([]string{"a", "b"}, map[string]string{"b": "2"}, []int{443}); g != w {
+		t.Errorf("values = %s, want %s", g, w)
+	}
+}
+
+func TestConfigFileKeysWithoutValues(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"app.json", `{"name": null, "tags": []}`},
+		{"app.yaml", "name:\ntags:\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			fs, got := newConfigSet(t)
+			path := writeConfig(t, tt.file, tt.content)
+			if err := fs.ParseArgs([]string{"--config", path}); err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != "default" {
+				t.Errorf("name = %q, want the default", got.Name)
+			}
+			var visited []string
+			fs.Visit(func(f *Flag) { visited = append(visited, f.Name) })
+			if len(visited) != 1 || visited[0] != "config" {
+				t.Errorf("visited %q, want only [config]", visited)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package greedyflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Enum Value Types ---
+
+// enumChoices holds the allowed values of an enum flag.
+type enumChoices struct {
+	choices         []string
+	caseInsensitive bool
+}
+
+// parse returns the canonical spelling of s, or an error listing the valid choices.
+func (e *enumChoices) parse(s string) (string, error) {
+	for _, c := range e.choices {
+		if c == s || (e.caseInsensitive && strings.EqualFold(c, s)) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("invalid choice %q (valid choices: %s)", s, strings.Join(e.choices, ", "))
+}
+
+// typeName renders the choices for help output, e.g. "{json|yaml|table}".
+func (e *enumChoices) typeName() string {
+	return "{" + strings.Join(e.choices, "|") + "}"
+}
+
+// enumValuer is implemented by values restricted to a set of choices.
+type enumValuer interface {
+	enum() *enumChoices
+}
+
+// -- enumValue --
+type enumValue struct {
+	p *string
+	*enumChoices
+}
+
+func newEnumValue(val string, p *string, choices *enumChoices) *enumValue {
+	*p = val
+	return &enumValue{p: p, enumChoices: choices}
+}
+func (e *enumValue) Set(s string) error {
+	v, err := e.parse(s)
+	if err != nil {
+		return err
+	}
+	*e.p = v
+	return nil
+}
+func (e *enumValue) String() string     { return *e.p }
+func (e *enumValue) enum() *enumChoices { return e.enumChoices }
+
+// -- enumSliceValue -- (Used for Greedy Flags)
+type enumSliceValue struct {
+	*sliceValue[string]
+	choices *enumChoices
+}
+
+func (e *enumSliceValue) typeName() string   { return e.choices.typeName() }
+func (e *enumSliceValue) enum() *enumChoices { return e.choices }
+
+// newEnumChoices validates the choice list and the default values against it.
+func newEnumChoices(name string, choices []string, defaults ...string) *enumChoices {
+	if len(choices) == 0 {
+		panic(fmt.Sprintf("greedyflag: enum flag %s has no choices", name))
+	}
+	e := &enumChoices{choices: append([]string(nil), choices...)}
+	for _, d := range defaults {
+		if _, err := e.parse(d); err != nil {
+			panic(fmt.Sprintf("greedyflag: default of enum flag %s: %v", name, err))
+		}
+	}
+	return e
+}
+
+// --- Enum Flag Definition Functions ---
+
+// EnumVarP defines a string flag with specified name, shorthand, default value, allowed choices, and usage string.
+// Values outside choices are rejected with an error listing the valid choices. The default must be
+// one of the choices or empty. The argument p points to a string variable in which to store the value of the flag.
+func (fs *FlagSet) EnumVarP(p *string, name string, shorthand string, value string, choices []string, usage string) {
+	var e *enumChoices
+	if value == "" {
+		e = newEnumChoices(name, choices)
+	} else {
+		e = newEnumChoices(name, choices, value)
+	}
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     newEnumValue(value, p, e),
+		DefValue:  value,
+	})
+}
+
+// EnumVarP defines a string flag with specified name, shorthand, default value, allowed choices, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+func EnumVarP(p *string, name string, shorthand string, value string, choices []string, usage string) {
+	CommandLine.EnumVarP(p, name, shorthand, value, choices, usage)
+}
+
+// EnumP is like EnumVarP, but returns a pointer to a string variable.
+func (fs *FlagSet) EnumP(name string, shorthand string, value string, choices []string, usage string) *string {
+	p := new(string)
+	fs.EnumVarP(p, name, shorthand, value, choices, usage)
+	return p
+}
+
+// EnumP is like EnumVarP, but returns a pointer to a string variable.
+func EnumP(name string, shorthand string, value string, choices []string, usage string) *string {
+	return CommandLine.EnumP(name, shorthand, value, choices, usage)
+}
+
+// EnumSliceGreedyVarP defines a greedy []string flag with specified name, shorthand, default value, allowed
+// choices, and usage string. Every consumed token must be one of choices.
+// The argument p points to a []string variable in which to store the values of the flag.
+func (fs *FlagSet) EnumSliceGreedyVarP(p *[]string, name string, shorthand string, value []string, choices []string, usage string) {
+	e := newEnumChoices(name, choices, value...)
+	// Create a copy of the default value slice to avoid modification issues
+	defaultValueCopy := make([]string, len(value))
+	copy(defaultValueCopy, value)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     &enumSliceValue{sliceValue: newSliceValue(defaultValueCopy, p, e.parse, formatString), choices: e},
+		DefValue:  newStringSliceValue(value, new([]string)).String(),
+		IsGreedy:  true,
+	})
+}
+
+// EnumSliceGreedyVarP defines a greedy []string flag with specified name, shorthand, default value, allowed
+// choices, and usage string. The argument p points to a []string variable in which to store the values of the flag.
+func EnumSliceGreedyVarP(p *[]string, name string, shorthand string, value []string, choices []string, usage string) {
+	CommandLine.EnumSliceGreedyVarP(p, name, shorthand, value, choices, usage)
+}
+
+// EnumSliceGreedyP is like EnumSliceGreedyVarP, but returns a pointer to a []string variable.
+func (fs *FlagSet) EnumSliceGreedyP(name string, shorthand string, value []string, choices []string, usage string) *[]string {
+	p := new([]string)
+	fs.EnumSliceGreedyVarP(p, name, shorthand, value, choices, usage)
+	return p
+}
+
+// EnumSliceGreedyP is like EnumSliceGreedyVarP, but returns a pointer to a []string variable.
+func EnumSliceGreedyP(name string, shorthand string, value []string, choices []string, usage string) *[]string {
+	return CommandLine.EnumSliceGreedyP(name, shorthand, value, choices, usage)
+}
+
+// SetEnumCaseInsensitive makes the named enum flag accept its choices regardless of case.
+// The matching choice is stored in its declared spelling. Returns ErrConfiguration if the
+// flag does not exist or is not an enum flag.
+func (fs *FlagSet) SetEnumCaseInsensitive(name string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	e, ok := f.Value.(enumValuer)
+	if !ok {
+		return fmt.Errorf("%w: flag --%s is not an enum flag", ErrConfiguration, name)
+	}
+	e.enum().caseInsensitive = true
+	return nil
+}
+
+// SetEnumCaseInsensitive makes the named enum flag of the default set accept its choices regardless of case.
+func SetEnumCaseInsensitive(name string) error {
+	return CommandLine.SetEnumCaseInsensitive(name)
+}
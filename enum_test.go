@@ -0,0 +1,107 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnumFlag(t *testing.T) {
+	tests := []struct {
+		name            string
+		caseInsensitive bool
+		args            []string
+		format          string
+		wantMsg         string // Empty for success
+	}{
+		{"default", false, nil, "table", ""},
+		{"valid choice", false, []string{"--format", "json"}, "json", ""},
+		{"invalid choice lists the choices", false, []string{"-f", "xml"}, "", `invalid choice "xml" (valid choices: json, yaml, table)`},
+		{"case matters by default", false, []string{"-f", "JSON"}, "", `invalid choice "JSON"`},
+		{"case-insensitive stores the declared spelling", true, []string{"--format=JSON"}, "json", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("t")
+			format := fs.EnumP("format", "f", "table", []string{"json", "yaml", "table"}, "Output format")
+			if tt.caseInsensitive {
+				if err := fs.SetEnumCaseInsensitive("format"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := fs.ParseArgs(tt.args)
+			if tt.wantMsg != "" {
+				if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Fatalf("ParseArgs(%q) error = %v, want ErrParsing containing %q", tt.args, err, tt.wantMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if *format != tt.format {
+				t.Errorf("format = %q, want %q", *format, tt.format)
+			}
+		})
+	}
+}
+
+func TestEnumSliceGreedyFlag(t *testing.T) {
+	fs := NewFlagSet("t")
+	langs := fs.EnumSliceGreedyP("langs", "l", nil, []string{"go", "py", "rs"}, "Languages")
+	if err := fs.SetEnumCaseInsensitive("langs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ParseArgs([]string{"-l", "go", "PY", "-l", "rs"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*langs, []string{"go", "py", "rs"}) {
+		t.Errorf("langs = %q, want [go py rs]", *langs)
+	}
+
+	fs = NewFlagSet("t")
+	fs.EnumSliceGreedyP("langs", "l", nil, []string{"go", "py"}, "Languages")
+	err := fs.ParseArgs([]string{"-l", "go", "c"})
+	if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), `invalid value "c" for greedy flag --langs: invalid choice "c" (valid choices: go, py)`) {
+		t.Errorf("ParseArgs error = %v, want ErrParsing for \"c\"", err)
+	}
+}
+
+func TestEnumConfiguration(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.StringP("name", "n", "", "Name")
+	for _, name := range []string{"nope", "name"} {
+		if err := fs.SetEnumCaseInsensitive(name); !errors.Is(err, ErrConfiguration) {
+			t.Errorf("SetEnumCaseInsensitive(%q) error = %v, want ErrConfiguration", name, err)
+		}
+	}
+	for name, define := range map[string]func(){
+		"no choices":         func() { fs.EnumP("a", "", "", nil, "") },
+		"bad default":        func() { fs.EnumP("b", "", "xml", []string{"json"}, "") },
+		"bad greedy default": func() { fs.EnumSliceGreedyP("c", "", []string{"xml"}, []string{"json"}, "") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			define()
+		}()
+	}
+}
+
+func TestEnumHelp(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.EnumP("format", "f", "table", []string{"json", "yaml", "table"}, "Output format")
+	fs.EnumSliceGreedyP("langs", "l", nil, []string{"go", "py"}, "Languages")
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	for _, want := range []string{"--format {json|yaml|table}", "--langs {go|py}"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("PrintDefaults output %q does not contain %q", out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package greedyflag
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// --- Environment Variable Binding ---
+
+// SetEnvVars binds the named flag to one or more environment variables. If the flag is not
+// given on the command line, the first of them that is set to a non-empty value is used
+// instead of the default. Returns ErrConfiguration if the flag does not exist.
+func (fs *FlagSet) SetEnvVars(name string, envVars ...string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	f.EnvVars = append([]string(nil), envVars...)
+	return nil
+}
+
+// SetEnvVars binds the named flag of the default set to one or more environment variables.
+// See FlagSet.SetEnvVars.
+func SetEnvVars(name string, envVars ...string) error {
+	return CommandLine.SetEnvVars(name, envVars...)
+}
+
+// SetEnvPrefix binds every flag without explicit EnvVars to an environment variable derived
+// from its long name: the prefix, an underscore, and the name in upper case with '-' replaced
+// by '_' (prefix "MYTOOL" maps MYTOOL_OUTPUT to --output and MYTOOL_DRY_RUN to --dry-run).
+// An empty prefix disables the automatic mapping. In a command tree, a subcommand whose sets
+// have no prefix uses the nearest ancestor command's, so "mytool serve" also reads MYTOOL_OUTPUT.
+func (fs *FlagSet) SetEnvPrefix(prefix string) {
+	fs.envPrefix = prefix
+}
+
+// SetEnvPrefix sets the automatic environment variable prefix of the default set.
+// See FlagSet.SetEnvPrefix.
+func SetEnvPrefix(prefix string) {
+	CommandLine.SetEnvPrefix(prefix)
+}
+
+// SetEnvSeparator sets the separator used to split environment values of greedy and remainder
+// flags into individual values (default ","). An empty separator passes the whole value as one.
+// Like the prefix, it is inherited by subcommands that do not set their own.
+func (fs *FlagSet) SetEnvSeparator(sep string) {
+	fs.envSeparator = sep
+	fs.envSeparatorSet = true
+}
+
+// SetEnvSeparator sets the environment value separator of the default set.
+// See FlagSet.SetEnvSeparator.
+func SetEnvSeparator(sep string) {
+	CommandLine.SetEnvSeparator(sep)
+}
+
+// inheritEnvSettings adopts the environment prefix and separator of from (a set of an
+// ancestor command) unless fs sets them itself. Callers pass the nearest sets first.
+func (fs *FlagSet) inheritEnvSettings(from *FlagSet) {
+	if fs.envPrefix == "" {
+		fs.envPrefix = from.envPrefix
+	}
+	if !fs.envSeparatorSet && from.envSeparatorSet {
+		fs.envSeparator = from.envSeparator
+		fs.envSeparatorSet = true
+	}
+}
+
+// envVarsFor returns the environment variables bound to f, explicit ones first.
+func (fs *FlagSet) envVarsFor(f *Flag) []string {
+	if len(f.EnvVars) > 0 || fs.envPrefix == "" || f.Name == "help" {
+		return f.EnvVars
+	}
+	return []string{fs.envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))}
+}
+
+// applyEnv sets flags not given on the command line from their environment variables,
+// so the command line takes precedence over the environment, and the environment over defaults.
+func (fs *FlagSet) applyEnv() error {
+	for _, f := range fs.sortedFlags() {
+		if f.changed {
+			continue
+		}
+		for _, env := range fs.envVarsFor(f) {
+			val, ok := os.LookupEnv(env)
+			if !ok || val == "" {
+				continue
+			}
+			values := []string{val}
+			if (f.IsGreedy || f.IsRemainder) && fs.envSeparator != "" {
+				values = strings.Split(val, fs.envSeparator)
+			}
+			if f.IsGreedy && (len(values) < f.MinArgs || (f.MaxArgs > 0 && len(values) > f.MaxArgs)) {
+				return fmt.Errorf("%w: flag --%s from environment variable %s expects %s, got %d", ErrParsing, f.Name, env, arityDesc(f), len(values))
+			}
+			resetValue(f.Value) // The environment replaces the default rather than adding to it
+			for _, v := range values {
+				if err := f.Value.Set(v); err != nil {
+					return fmt.Errorf("%w: invalid value %q for flag --%s from environment variable %s: %v", ErrParsing, v, f.Name, env, err)
+				}
+			}
+			f.changed = true
+			f.Source = ValueSource{Kind: SourceEnvironment, EnvVar: env}
+			slog.Debug("Flag set from environment", "flag", f.Name, "env", env, "value", val)
+			break // The first set variable wins
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEnvPrecedence(t *testing.T) {
+	t.Setenv("MYTOOL_OUTPUT", "/tmp/env")
+	t.Setenv("MYTOOL_DRY_RUN", "true")
+	t.Setenv("MYTOOL_EXTENSIONS", "go,py")
+	t.Setenv("LEVEL", "3")
+
+	fs := NewFlagSet("mytool")
+	fs.SetEnvPrefix("MYTOOL")
+	output := fs.StringP("output", "o", "out", "Output")
+	dryRun := fs.BoolP("dry-run", "n", false, "Dry run")
+	exts := fs.StringSliceGreedyP("extensions", "e", nil, "Extensions")
+	level := fs.IntP("level", "l", 1, "Level")
+	if err := fs.SetEnvVars("level", "NOT_SET", "LEVEL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ParseArgs([]string{"-o", "/tmp/cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if *output != "/tmp/cli" {
+		t.Errorf("output = %q, want the command line value", *output)
+	}
+	if !*dryRun || *level != 3 || !reflect.DeepEqual(*exts, []string{"go", "py"}) {
+		t.Errorf("dry-run = %v, level = %d, extensions = %q; want true, 3, [go py]", *dryRun, *level, *exts)
+	}
+	if src := fs.Lookup("level").Source; src.Kind != SourceEnvironment || src.EnvVar != "LEVEL" {
+		t.Errorf("level source = %v, want env $LEVEL", src)
+	}
+}
+
+func TestEnvInvalidValue(t *testing.T) {
+	t.Setenv("MYTOOL_LEVEL", "high")
+	fs := NewFlagSet("mytool")
+	fs.SetEnvPrefix("MYTOOL")
+	fs.IntP("level", "l", 1, "Level")
+	if err := fs.ParseArgs(nil); !errors.Is(err, ErrParsing) {
+		t.Fatalf("ParseArgs error = %v, want ErrParsing", err)
+	}
+}
+
+func TestEnvInheritedBySubcommands(t *testing.T) {
+	t.Setenv("MYTOOL_OUT", "/tmp/env")
+	t.Setenv("MYTOOL_PORTS", "80;443")
+	t.Setenv("SERVE_OUT", "/tmp/serve")
+
+	tests := []struct {
+		name   string
+		prefix func(root, serve *Command)
+		want   string
+	}{
+		{"root flags", func(root, _ *Command) { root.Flags().SetEnvPrefix("MYTOOL") }, "/tmp/env"},
+		{"root persistent flags", func(root, _ *Command) { root.PersistentFlags().SetEnvPrefix("MYTOOL") }, "/tmp/env"},
+		{"own prefix wins", func(root, serve *Command) {
+			root.Flags().SetEnvPrefix("MYTOOL")
+			serve.Flags().SetEnvPrefix("SERVE")
+		}, "/tmp/serve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewCommand("mytool", "", nil)
+			root.Flags().SetEnvSeparator(";")
+			out := root.PersistentFlags().StringP("out", "o", "", "Output")
+			var ports []int
+			serve := NewCommand("serve", "Serve", func(*Command, []string) error { return nil })
+			serve.Flags().IntSliceGreedyVarP(&ports, "ports", "p", nil, "Ports")
+			root.AddCommand(serve)
+			tt.prefix(root, serve)
+			if err := root.ExecuteArgs([]string{"serve"}); err != nil {
+				t.Fatal(err)
+			}
+			if *out != tt.want {
+				t.Errorf("out = %q, want %q", *out, tt.want)
+			}
+			if tt.want == "/tmp/env" && !reflect.DeepEqual(ports, []int{80, 443}) {
+				t.Errorf("ports = %v, want [80 443] split on the inherited separator", ports)
+			}
+		})
+	}
+}
+
+func TestVisitIncludesEnvironment(t *testing.T) {
+	t.Setenv("MYTOOL_OUTPUT", "/tmp/env")
+	fs := NewFlagSet("mytool")
+	fs.SetEnvPrefix("MYTOOL")
+	fs.StringP("output", "o", "", "Output")
+	fs.BoolP("verbose", "v", false, "Verbose")
+	fs.IntP("level", "l", 1, "Level")
+	if err := fs.ParseArgs([]string{"-v"}); err != nil {
+		t.Fatal(err)
+	}
+	var visited []string
+	fs.Visit(func(f *Flag) { visited = append(visited, f.Name+"="+f.Source.Kind.String()) })
+	if want := []string{"output=env", "verbose=command line"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited %q, want %q", visited, want)
+	}
+}
+
+func TestEnvReplacesGreedyDefault(t *testing.T) {
+	t.Setenv("T_EXT", "py,rs")
+	t.Setenv("T_LABEL", "b=2")
+	t.Setenv("T_NUMS", "3")
+	fs := NewFlagSet("t")
+	fs.SetEnvPrefix("T")
+	ext := fs.StringSliceGreedyP("ext", "e", []string{"go"}, "Extensions")
+	label := fs.StringToIntGreedyP("label", "l", map[string]int{"a": 1}, "Labels")
+	nums := fs.IntSliceGreedyP("nums", "n", []int{1, 2}, "Numbers")
+	if err := fs.ParseArgs(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*ext, []string{"py", "rs"}) {
+		t.Errorf("ext = %q, want [py rs]", *ext)
+	}
+	if !reflect.DeepEqual(*label, map[string]int{"b": 2}) {
+		t.Errorf("label = %v, want map[b:2]", *label)
+	}
+	if !reflect.DeepEqual(*nums, []int{3}) {
+		t.Errorf("nums = %v, want [3]", *nums)
+	}
+}
+
+func TestEnvGreedyArity(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"a", true},
+		{"a,b", false},
+		{"a,b,c", false},
+		{"a,b,c,d", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("T_EXT", tt.value)
+		fs := NewFlagSet("t")
+		fs.SetEnvPrefix("T")
+		fs.StringSliceGreedyP("ext", "e", nil, "Extensions")
+		if err := fs.SetGreedyArity("ext", 2, 3); err != nil {
+			t.Fatal(err)
+		}
+		err := fs.ParseArgs(nil)
+		if tt.wantErr != errors.Is(err, ErrParsing) {
+			t.Errorf("T_EXT=%s: ParseArgs error = %v, want error %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
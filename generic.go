@@ -0,0 +1,129 @@
+package greedyflag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"unicode"
+)
+
+// --- Generic Value Types ---
+
+// -- genericValue -- (Scalar flag of any type with a caller-supplied parser)
+type genericValue[T any] struct {
+	p     *T
+	parse func(string) (T, error)
+	typ   string // Type name shown by PrintDefaults
+}
+
+func newGenericValue[T any](val T, p *T, parse func(string) (T, error)) *genericValue[T] {
+	*p = val
+	return &genericValue[T]{p: p, parse: parse, typ: typeNameOf[T]()}
+}
+func (g *genericValue[T]) Set(s string) error {
+	v, err := g.parse(s)
+	if err != nil {
+		return err
+	}
+	*g.p = v
+	return nil
+}
+func (g *genericValue[T]) String() string {
+	if g.p == nil {
+		return ""
+	}
+	return formatValue(*g.p)
+}
+func (g *genericValue[T]) typeName() string { return g.typ }
+
+// typeNamer is implemented by values that know the type name to show in help output.
+type typeNamer interface {
+	typeName() string
+}
+
+// typeNameOf derives a help type name from T, e.g. "version" for semver.Version
+// or "prefix" for netip.Prefix. Unnamed types use their Go syntax (e.g. "[]byte").
+func typeNameOf[T any]() string {
+	return typeNameOfType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// typeNameOfType is typeNameOf for a reflect.Type.
+func typeNameOfType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if name == "" {
+		return t.String()
+	}
+	return lowerInitialism(name)
+}
+
+// lowerInitialism lowercases the leading run of capitals of a Go type name, keeping the
+// last one if it starts the next word: "URL" → "url", "IPNet" → "ipNet", "Version" → "version".
+func lowerInitialism(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// formatValue renders a value for DefValue and help output, preferring
+// MarshalText (the inverse of UnmarshalText) over String.
+func formatValue[T any](v T) string {
+	if m, ok := any(v).(encoding.TextMarshaler); ok {
+		return marshalText(m)
+	}
+	if m, ok := any(&v).(encoding.TextMarshaler); ok {
+		return marshalText(m)
+	}
+	return fmt.Sprint(v)
+}
+
+// --- Generic Flag Definition Functions ---
+
+// Var defines a flag of any type T in fs with specified name, shorthand, default value,
+// and usage string. Each command-line value is converted with parse; a parse error is
+// reported as ErrParsing naming the flag. The argument p points to a T variable in which
+// to store the value of the flag. Use CommandLine as fs for the default set.
+//
+// The help type name is derived from T (e.g. "prefix" for netip.Prefix), and the default
+// value is shown using its MarshalText or String method if it has one.
+func Var[T any](fs *FlagSet, p *T, name string, shorthand string, value T, usage string, parse func(string) (T, error)) {
+	addGenericFlag(fs, p, name, shorthand, value, usage, parse)
+}
+
+// addGenericFlag defines a scalar flag backed by a genericValue. Internal use.
+func addGenericFlag[T any](fs *FlagSet, p *T, name string, shorthand string, value T, usage string, parse func(string) (T, error)) *genericValue[T] {
+	v := newGenericValue(value, p, parse)
+	defValStr := ""
+	if !reflect.ValueOf(&value).Elem().IsZero() {
+		// Zero values of many types print oddly (e.g. "invalid Prefix"), so leave them out
+		defValStr = v.String()
+	}
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  defValStr,
+	})
+	return v
+}
+
+// GreedySliceVar defines a greedy []T flag in fs with specified name, shorthand, default
+// value, and usage string. Each consumed token is converted with parse and appended.
+// The argument p points to a []T variable in which to store the values of the flag.
+// Use CommandLine as fs for the default set.
+func GreedySliceVar[T any](fs *FlagSet, p *[]T, name string, shorthand string, value []T, usage string, parse func(string) (T, error)) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parse, formatValue[T])
+	v.typ = typeNameOf[T]()
+}
@@ -0,0 +1,40 @@
+package greedyflag
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypeNameOfType(t *testing.T) {
+	tests := []struct {
+		typ  reflect.Type
+		want string
+	}{
+		{reflect.TypeOf(url.URL{}), "url"},
+		{reflect.TypeOf(&url.URL{}), "url"},
+		{reflect.TypeOf(net.IPNet{}), "ipNet"},
+		{reflect.TypeOf(net.IP{}), "ip"},
+		{reflect.TypeOf(netip.Prefix{}), "prefix"},
+		{reflect.TypeOf(DateRange{}), "dateRange"},
+		{reflect.TypeOf(time.Duration(0)), "duration"},
+		{reflect.TypeOf([]byte(nil)), "[]uint8"},
+	}
+	for _, tt := range tests {
+		if got := typeNameOfType(tt.typ); got != tt.want {
+			t.Errorf("typeNameOfType(%v) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestVarHelpTypeName(t *testing.T) {
+	fs := NewFlagSet("test")
+	var u *url.URL
+	Var(fs, &u, "uu", "", nil, "endpoint", url.Parse)
+	if name, _ := flagType(fs.Lookup("uu")); name != "url" {
+		t.Errorf("help type name = %q, want %q", name, "url")
+	}
+}
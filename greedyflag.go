@@ -7,11 +7,13 @@ package greedyflag
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -47,8 +49,27 @@ type Flag struct {
 	DefValue  string // Default value as text (used for help message).
 	IsGreedy  bool   // Is this a greedy slice flag?
 	IsBool    bool   // Is this a boolean flag (special parsing)?
+	IsCount   bool   // Is this a counter flag (no argument, incremented per occurrence)?
+	Negatable bool   // Does this boolean flag also accept --no-<name>? (See SetNegatable.)
+	// IsRemainder marks a flag that takes every remaining token verbatim. (See RemainderVarP.)
+	IsRemainder bool
+	// NoOptDefVal is the value used when a value flag is given without an argument
+	// (e.g. "auto" for a bare --color). Empty means an argument is required. (See SetNoOptDefVal.)
+	NoOptDefVal string
+	// MinArgs and MaxArgs bound the number of values one occurrence of a greedy flag
+	// consumes; MaxArgs 0 means no upper limit. (See SetGreedyArity.)
+	MinArgs int
+	MaxArgs int
+	// Terminator, if set, makes a greedy flag consume every following token, including
+	// dash-prefixed ones and "--", until this token. (See SetGreedyTerminator.)
+	Terminator string
+	// EnvVars lists environment variables consulted, in order, when the flag is not given on
+	// the command line. (See SetEnvVars and SetEnvPrefix.)
+	EnvVars []string
+	// Source records where the flag's current value came from; set by Parse.
+	Source ValueSource
 	// Internal state
-	changed bool // True if flag was set by the user on the command line.
+	changed bool // True if flag was set on the command line, in the environment or in a config file.
 }
 
 // --- Concrete Value Types ---
@@ -108,18 +129,53 @@ func (s *stringSliceValue) String() string {
 	// Quote elements containing spaces or commas? For now, just join.
 	return "[" + strings.Join(*s, ",") + "]"
 }
+func (s *stringSliceValue) reset() { *s = []string{} }
 
-// --- Global State (Default Command Set) ---
-var (
-	flags                            = make(map[string]*Flag) // Map long name to Flag
-	shortFlags                       = make(map[rune]*Flag)   // Map shorthand rune to Flag
-	args                             = []string{}             // Stores final positional args found by Parse()
-	parsed                           = false                  // Has Parse() been called?
-	hasBeenConfigured                = false                  // Prevent config changes after first flag definition
-	posMode           positionalMode = modeNone               // Default: no positionals
-	mandatoryN        int            = -1                     // N for MandatoryN mode (-1 means not set)
-	allowHelpFlag     bool           = true                   // Automatically handle -h/--help? (Can be disabled)
-)
+// resetter is implemented by values whose Set appends (greedy slices and maps), so that a
+// value from the environment or a config file can replace the default instead of adding to it.
+type resetter interface {
+	reset()
+}
+
+// resetValue clears v if it accumulates values.
+func resetValue(v Value) {
+	if r, ok := v.(resetter); ok {
+		r.reset()
+	}
+}
+
+// --- FlagSet ---
+
+// FlagSet represents a set of defined flags together with the positional
+// argument configuration used when parsing them. Each FlagSet owns its own
+// state, so several independent parsers can coexist in one process.
+// Create one with NewFlagSet; the zero value is not usable.
+type FlagSet struct {
+	// Usage is the function called when help is requested or parsing fails.
+	// It may be replaced with a custom function.
+	Usage func()
+
+	name              string
+	output            io.Writer        // nil means os.Stderr
+	flags             map[string]*Flag // Map long name to Flag
+	shortFlags        map[rune]*Flag   // Map shorthand rune to Flag
+	args              []string         // Stores final positional args found by Parse()
+	parsed            bool             // Has Parse() been called?
+	hasBeenConfigured bool             // Prevent config changes after first flag definition
+	posMode           positionalMode   // Default: no positionals
+	mandatoryN        int              // N for MandatoryN mode (-1 means not set); the minimum for a range
+	mandatoryMax      int              // Maximum for MandatoryN mode (-1 means no upper limit)
+	allowHelpFlag     bool             // Automatically handle -h/--help? (Can be disabled)
+	reserveTrailing   bool             // Take trailing positionals back from a greedy flag? (See SetReserveTrailingPositionals.)
+	cmd               *Command         // Owning command, if the set belongs to a command tree
+	positionals       []*positionalArg // Named positionals declared with PositionalVar
+	envPrefix         string           // Prefix for automatic environment variable names (empty: none)
+	envSeparator      string           // Splits environment values of greedy flags
+	envSeparatorSet   bool             // Was envSeparator set explicitly? (Otherwise inherited in a command tree.)
+	configFlag        *Flag            // Flag naming the config file (See ConfigFileVarP.)
+	argOffset         int              // Index of the first parsed argument in the full command line (See ValueSource.ArgIndex.)
+	unknownKeyPolicy  UnknownKeyPolicy // How unknown config file keys are treated
+}
 
 type positionalMode int
 
@@ -127,16 +183,81 @@ const (
 	modeNone positionalMode = iota
 	modeArbitraryLeading
 	modeMandatoryN
+	modeArbitraryTrailing
+	modeInterspersed
 )
 
+// NewFlagSet returns a new, empty flag set with the specified name.
+// The name is used as the program name in the default usage message.
+func NewFlagSet(name string) *FlagSet {
+	fs := &FlagSet{
+		name:          name,
+		flags:         make(map[string]*Flag),
+		shortFlags:    make(map[rune]*Flag),
+		args:          []string{},
+		posMode:       modeNone,
+		mandatoryN:    -1,
+		mandatoryMax:  -1,
+		allowHelpFlag: true,
+		envSeparator:  ",",
+	}
+	fs.Usage = fs.defaultUsage
+	return fs
+}
+
+// CommandLine is the default set of command-line flags, parsed from os.Args.
+// The top-level functions such as StringVarP and Parse are wrappers for the
+// methods of CommandLine.
+var CommandLine = NewFlagSet(os.Args[0])
+
+func init() {
+	// Override the generic FlagSet default so that replacing the
+	// package-level Usage variable also affects CommandLine.
+	CommandLine.Usage = commandLineUsage
+}
+
+func commandLineUsage() {
+	Usage()
+}
+
+// Name returns the name of the flag set.
+func (fs *FlagSet) Name() string {
+	return fs.name
+}
+
+// Output returns the destination for usage and warning messages.
+// If output was not set, a subcommand's set uses its parent's output and any
+// other set uses os.Stderr.
+func (fs *FlagSet) Output() io.Writer {
+	if fs.output == nil {
+		if fs.cmd != nil && fs.cmd.parent != nil {
+			return fs.cmd.parent.flags.Output()
+		}
+		return os.Stderr
+	}
+	return fs.output
+}
+
+// SetOutput sets the destination for usage and warning messages.
+// If w is nil, os.Stderr is used.
+func (fs *FlagSet) SetOutput(w io.Writer) {
+	fs.output = w
+}
+
 // --- Flag Definition Functions ---
 
-// AddFlag adds a flag definition to the default set. Internal use.
-func addFlag(f *Flag) {
-	if _, exists := flags[f.Name]; exists {
+// addFlag adds a flag definition to the set. Internal use.
+func (fs *FlagSet) addFlag(f *Flag) {
+	if _, exists := fs.flags[f.Name]; exists {
 		// Use panic because this is a programmer error (defining flags twice)
 		panic(fmt.Sprintf("greedyflag: flag redefined: %s", f.Name))
 	}
+	if neg := fs.negatedFlag(f.Name); neg != nil {
+		panic(fmt.Sprintf("greedyflag: flag redefined: %s (negation of --%s)", f.Name, neg.Name))
+	}
+	if r := fs.remainderFlag(); f.IsRemainder && r != nil {
+		panic(fmt.Sprintf("greedyflag: only one remainder flag allowed per set: --%s and --%s", r.Name, f.Name))
+	}
 	if f.Shorthand != "" {
 		// Validate shorthand is single character
 		if len(f.Shorthand) != 1 {
@@ -144,19 +265,19 @@ func addFlag(f *Flag) {
 		}
 		// Get the rune for the map key
 		shorthandRune, _ := utf8.DecodeRuneInString(f.Shorthand) // Get first rune
-		if _, exists := shortFlags[shorthandRune]; exists {
+		if _, exists := fs.shortFlags[shorthandRune]; exists {
 			panic(fmt.Sprintf("greedyflag: flag shorthand redefined: -%s", f.Shorthand))
 		}
-		shortFlags[shorthandRune] = f
+		fs.shortFlags[shorthandRune] = f
 	}
-	flags[f.Name] = f
-	hasBeenConfigured = true // Lock positional config once flags are defined
+	fs.flags[f.Name] = f
+	fs.hasBeenConfigured = true // Lock positional config once flags are defined
 }
 
 // StringVarP defines a string flag with specified name, shorthand, default value, and usage string.
 // The argument p points to a string variable in which to store the value of the flag.
-func StringVarP(p *string, name string, shorthand string, value string, usage string) {
-	addFlag(&Flag{
+func (fs *FlagSet) StringVarP(p *string, name string, shorthand string, value string, usage string) {
+	fs.addFlag(&Flag{
 		Name:      name,
 		Shorthand: shorthand,
 		Usage:     usage,
@@ -165,17 +286,28 @@ func StringVarP(p *string, name string, shorthand string, value string, usage st
 	})
 }
 
+// StringVarP defines a string flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+func StringVarP(p *string, name string, shorthand string, value string, usage string) {
+	CommandLine.StringVarP(p, name, shorthand, value, usage)
+}
+
 // StringP is like StringVarP, but returns a pointer to a string variable.
-func StringP(name string, shorthand string, value string, usage string) *string {
+func (fs *FlagSet) StringP(name string, shorthand string, value string, usage string) *string {
 	p := new(string)
-	StringVarP(p, name, shorthand, value, usage)
+	fs.StringVarP(p, name, shorthand, value, usage)
 	return p
 }
 
+// StringP is like StringVarP, but returns a pointer to a string variable.
+func StringP(name string, shorthand string, value string, usage string) *string {
+	return CommandLine.StringP(name, shorthand, value, usage)
+}
+
 // BoolVarP defines a bool flag with specified name, shorthand, default value, and usage string.
 // The argument p points to a bool variable in which to store the value of the flag.
-func BoolVarP(p *bool, name string, shorthand string, value bool, usage string) {
-	addFlag(&Flag{
+func (fs *FlagSet) BoolVarP(p *bool, name string, shorthand string, value bool, usage string) {
+	fs.addFlag(&Flag{
 		Name:      name,
 		Shorthand: shorthand,
 		Usage:     usage,
@@ -185,23 +317,34 @@ func BoolVarP(p *bool, name string, shorthand string, value bool, usage string)
 	})
 }
 
+// BoolVarP defines a bool flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a bool variable in which to store the value of the flag.
+func BoolVarP(p *bool, name string, shorthand string, value bool, usage string) {
+	CommandLine.BoolVarP(p, name, shorthand, value, usage)
+}
+
 // BoolP is like BoolVarP, but returns a pointer to a bool variable.
-func BoolP(name string, shorthand string, value bool, usage string) *bool {
+func (fs *FlagSet) BoolP(name string, shorthand string, value bool, usage string) *bool {
 	p := new(bool)
-	BoolVarP(p, name, shorthand, value, usage)
+	fs.BoolVarP(p, name, shorthand, value, usage)
 	return p
 }
 
+// BoolP is like BoolVarP, but returns a pointer to a bool variable.
+func BoolP(name string, shorthand string, value bool, usage string) *bool {
+	return CommandLine.BoolP(name, shorthand, value, usage)
+}
+
 // StringSliceGreedyVarP defines a greedy []string flag with specified name, shorthand, default value, and usage string.
 // The argument p points to a []string variable in which to store the values of the flag.
-func StringSliceGreedyVarP(p *[]string, name string, shorthand string, value []string, usage string) {
+func (fs *FlagSet) StringSliceGreedyVarP(p *[]string, name string, shorthand string, value []string, usage string) {
 	// Create a copy of the default value slice to avoid modification issues
 	defaultValueCopy := make([]string, len(value))
 	copy(defaultValueCopy, value)
 	// Store default value representation for help message
 	defValStr := newStringSliceValue(defaultValueCopy, new([]string)).String()
 
-	addFlag(&Flag{
+	fs.addFlag(&Flag{
 		Name:      name,
 		Shorthand: shorthand,
 		Usage:     usage,
@@ -211,69 +354,401 @@ func StringSliceGreedyVarP(p *[]string, name string, shorthand string, value []s
 	})
 }
 
+// StringSliceGreedyVarP defines a greedy []string flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []string variable in which to store the values of the flag.
+func StringSliceGreedyVarP(p *[]string, name string, shorthand string, value []string, usage string) {
+	CommandLine.StringSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// StringSliceGreedyP is like StringSliceGreedyVarP, but returns a pointer to a []string variable.
+func (fs *FlagSet) StringSliceGreedyP(name string, shorthand string, value []string, usage string) *[]string {
+	p := new([]string)
+	fs.StringSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
 // StringSliceGreedyP is like StringSliceGreedyVarP, but returns a pointer to a []string variable.
 func StringSliceGreedyP(name string, shorthand string, value []string, usage string) *[]string {
+	return CommandLine.StringSliceGreedyP(name, shorthand, value, usage)
+}
+
+// RemainderVarP defines a remainder flag with specified name, shorthand, and usage string.
+// The flag takes every token after it verbatim, including ones that look like flags and "--",
+// so --cmd make -j8 --keep-going yields [make -j8 --keep-going]. An attached value
+// (--cmd=make) becomes the first element. At most one remainder flag may be defined per set.
+// The argument p points to a []string variable in which to store the tokens.
+func (fs *FlagSet) RemainderVarP(p *[]string, name string, shorthand string, usage string) {
+	fs.addFlag(&Flag{
+		Name:        name,
+		Shorthand:   shorthand,
+		Usage:       usage,
+		Value:       newStringSliceValue(nil, p),
+		DefValue:    "[]",
+		IsRemainder: true,
+	})
+}
+
+// RemainderVarP defines a remainder flag with specified name, shorthand, and usage string.
+// The argument p points to a []string variable in which to store the tokens.
+func RemainderVarP(p *[]string, name string, shorthand string, usage string) {
+	CommandLine.RemainderVarP(p, name, shorthand, usage)
+}
+
+// RemainderP is like RemainderVarP, but returns a pointer to a []string variable.
+func (fs *FlagSet) RemainderP(name string, shorthand string, usage string) *[]string {
 	p := new([]string)
-	StringSliceGreedyVarP(p, name, shorthand, value, usage)
+	fs.RemainderVarP(p, name, shorthand, usage)
 	return p
 }
 
+// RemainderP is like RemainderVarP, but returns a pointer to a []string variable.
+func RemainderP(name string, shorthand string, usage string) *[]string {
+	return CommandLine.RemainderP(name, shorthand, usage)
+}
+
+// remainderFlag returns the set's remainder flag, or nil.
+func (fs *FlagSet) remainderFlag() *Flag {
+	for _, f := range fs.flags {
+		if f.IsRemainder {
+			return f
+		}
+	}
+	return nil
+}
+
+// negatedFlag returns the negatable boolean flag that name ("no-<flag>") turns off, or nil.
+func (fs *FlagSet) negatedFlag(name string) *Flag {
+	base, found := strings.CutPrefix(name, "no-")
+	if !found {
+		return nil
+	}
+	if f := fs.flags[base]; f != nil && f.Negatable {
+		return f
+	}
+	return nil
+}
+
+// SetGreedyArity bounds the number of values one occurrence of the named greedy flag consumes.
+// Fewer than lo values is a parse error; once hi values have been consumed, the flag stops
+// consuming and the remaining tokens are handled as flags or positionals. hi <= 0 means no
+// upper limit. Returns ErrConfiguration if the flag does not exist, is not greedy, or the
+// bounds are invalid.
+func (fs *FlagSet) SetGreedyArity(name string, lo int, hi int) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	if !f.IsGreedy {
+		return fmt.Errorf("%w: flag --%s is not a greedy flag", ErrConfiguration, name)
+	}
+	if hi < 0 {
+		hi = 0
+	}
+	if lo < 0 || (hi > 0 && hi < lo) {
+		return fmt.Errorf("%w: invalid arity for flag --%s: min %d, max %d", ErrConfiguration, name, lo, hi)
+	}
+	f.MinArgs = lo
+	f.MaxArgs = hi
+	return nil
+}
+
+// SetGreedyArity bounds the number of values one occurrence of the named greedy flag of the
+// default set consumes. See FlagSet.SetGreedyArity.
+func SetGreedyArity(name string, lo int, hi int) error {
+	return CommandLine.SetGreedyArity(name, lo, hi)
+}
+
+// SetGreedyNArgs makes the named greedy flag consume exactly n values per occurrence
+// (like argparse's nargs=N). It is shorthand for SetGreedyArity(name, n, n).
+func (fs *FlagSet) SetGreedyNArgs(name string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("%w: flag --%s must take at least one value", ErrConfiguration, name)
+	}
+	return fs.SetGreedyArity(name, n, n)
+}
+
+// SetGreedyNArgs makes the named greedy flag of the default set consume exactly n values per occurrence.
+func SetGreedyNArgs(name string, n int) error {
+	return CommandLine.SetGreedyNArgs(name, n)
+}
+
+// SetGreedyTerminator makes the named greedy flag consume every following token verbatim,
+// including dash-prefixed tokens and "--", until a token equal to terminator (like find's
+// -exec ... ';'). The terminator itself is discarded; a missing terminator is a parse error.
+// A value attached with '=' (--exec=rm) is the first value, and consumption continues up to
+// the terminator. An empty terminator restores normal greedy behaviour. Returns ErrConfiguration if the flag
+// does not exist or is not greedy.
+func (fs *FlagSet) SetGreedyTerminator(name string, terminator string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	if !f.IsGreedy {
+		return fmt.Errorf("%w: flag --%s is not a greedy flag", ErrConfiguration, name)
+	}
+	f.Terminator = terminator
+	return nil
+}
+
+// SetGreedyTerminator makes the named greedy flag of the default set consume every following
+// token until terminator. See FlagSet.SetGreedyTerminator.
+func SetGreedyTerminator(name string, terminator string) error {
+	return CommandLine.SetGreedyTerminator(name, terminator)
+}
+
+// arityDesc describes the arity of a greedy flag, e.g. "2 value(s)" or "1-3 values".
+func arityDesc(f *Flag) string {
+	switch {
+	case f.MinArgs == f.MaxArgs:
+		return fmt.Sprintf("%d value(s)", f.MinArgs)
+	case f.MaxArgs == 0:
+		return fmt.Sprintf("at least %d value(s)", f.MinArgs)
+	default:
+		return fmt.Sprintf("%d-%d values", f.MinArgs, f.MaxArgs)
+	}
+}
+
+// arityError reports a greedy flag occurrence that consumed too few values.
+func arityError(f *Flag, got int) error {
+	return fmt.Errorf("%w: flag --%s expects %s, got %d", ErrParsing, f.Name, arityDesc(f), got)
+}
+
+// SetNoOptDefVal lets the named value flag be given without an argument, in which case it is
+// set to value (e.g. a bare --color means "auto", while --color=always still works). A bare flag
+// never consumes the following token, so an explicit value must be attached with '='.
+// Returns ErrConfiguration if the flag does not exist, is a boolean, counter or greedy flag,
+// or if value is empty.
+func (fs *FlagSet) SetNoOptDefVal(name string, value string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	if f.IsBool || f.IsCount || f.IsGreedy || f.IsRemainder {
+		return fmt.Errorf("%w: flag --%s does not take a single value and cannot have an optional value", ErrConfiguration, name)
+	}
+	if value == "" {
+		return fmt.Errorf("%w: optional value for flag --%s cannot be empty", ErrConfiguration, name)
+	}
+	f.NoOptDefVal = value
+	return nil
+}
+
+// SetNoOptDefVal lets the named value flag of the default set be given without an argument.
+// See FlagSet.SetNoOptDefVal.
+func SetNoOptDefVal(name string, value string) error {
+	return CommandLine.SetNoOptDefVal(name, value)
+}
+
+// SetNegatable makes the named boolean flag also accept --no-<name>, which sets it to false.
+// Returns ErrConfiguration if the flag does not exist, is not a boolean flag, or if a flag
+// named no-<name> is already defined.
+func (fs *FlagSet) SetNegatable(name string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	if !f.IsBool {
+		return fmt.Errorf("%w: flag --%s is not a boolean flag and cannot be negated", ErrConfiguration, name)
+	}
+	if _, exists := fs.flags["no-"+name]; exists {
+		return fmt.Errorf("%w: cannot make --%s negatable: flag --no-%s is already defined", ErrConfiguration, name, name)
+	}
+	f.Negatable = true
+	return nil
+}
+
+// SetNegatable makes the named boolean flag of the default set also accept --no-<name>.
+func SetNegatable(name string) error {
+	return CommandLine.SetNegatable(name)
+}
+
 // --- Positional Config Functions ---
 
 // checkPositionalConfigConflict ensures only one positional mode is set before flags are defined.
-func checkPositionalConfigConflict(newMode positionalMode) error {
-	if hasBeenConfigured {
+func (fs *FlagSet) checkPositionalConfigConflict(newMode positionalMode) error {
+	if fs.hasBeenConfigured {
 		return fmt.Errorf("%w: cannot change positional argument mode after flags have been defined", ErrConfiguration)
 	}
-	if posMode != modeNone && posMode != newMode {
-		return fmt.Errorf("%w: cannot set multiple positional argument modes (current: %v, new: %v)", ErrConfiguration, posMode, newMode)
+	if fs.posMode != modeNone && fs.posMode != newMode {
+		return fmt.Errorf("%w: cannot set multiple positional argument modes (current: %v, new: %v)", ErrConfiguration, fs.posMode, newMode)
 	}
 	return nil
 }
 
 // AllowArbitraryLeadingPositionals configures the parser to accept zero or more
 // positional arguments only before the first flag is encountered.
-// This call is mutually exclusive with SetMandatoryNArgs. Must be called before defining flags or Parse.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func (fs *FlagSet) AllowArbitraryLeadingPositionals() error {
+	if err := fs.checkPositionalConfigConflict(modeArbitraryLeading); err != nil {
+		return err
+	}
+	fs.posMode = modeArbitraryLeading
+	fs.mandatoryN = -1 // Ensure N is not set
+	slog.Debug("Positional mode set: Arbitrary Leading", "set", fs.name)
+	return nil
+}
+
+// AllowArbitraryLeadingPositionals configures the default set to accept zero or more
+// positional arguments only before the first flag is encountered.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
 func AllowArbitraryLeadingPositionals() error {
-	if err := checkPositionalConfigConflict(modeArbitraryLeading); err != nil {
+	return CommandLine.AllowArbitraryLeadingPositionals()
+}
+
+// AllowArbitraryTrailingPositionals configures the parser to accept zero or more
+// positional arguments after the flags ("cmd [flags] file..."). Like the standard flag
+// package, flag parsing stops at the first non-flag token that is not consumed by a flag,
+// and everything from there on is positional; "--" ends flag parsing explicitly.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func (fs *FlagSet) AllowArbitraryTrailingPositionals() error {
+	if err := fs.checkPositionalConfigConflict(modeArbitraryTrailing); err != nil {
+		return err
+	}
+	fs.posMode = modeArbitraryTrailing
+	fs.mandatoryN = -1 // Ensure N is not set
+	slog.Debug("Positional mode set: Arbitrary Trailing", "set", fs.name)
+	return nil
+}
+
+// AllowArbitraryTrailingPositionals configures the default set to accept zero or more
+// positional arguments after the flags. See FlagSet.AllowArbitraryTrailingPositionals.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func AllowArbitraryTrailingPositionals() error {
+	return CommandLine.AllowArbitraryTrailingPositionals()
+}
+
+// AllowInterspersedPositionals configures the parser to accept positional arguments anywhere
+// among the flags, GNU/pflag style: every non-flag token not consumed by a flag is collected,
+// in order ("cmd a -v b" yields Args() = [a b]). Tokens after "--" are always positional.
+// Note that a greedy flag still consumes the non-flag tokens that follow it.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func (fs *FlagSet) AllowInterspersedPositionals() error {
+	if err := fs.checkPositionalConfigConflict(modeInterspersed); err != nil {
 		return err
 	}
-	posMode = modeArbitraryLeading
-	mandatoryN = -1 // Ensure N is not set
-	slog.Debug("Positional mode set: Arbitrary Leading")
+	fs.posMode = modeInterspersed
+	fs.mandatoryN = -1 // Ensure N is not set
+	slog.Debug("Positional mode set: Interspersed", "set", fs.name)
 	return nil
 }
 
+// AllowInterspersedPositionals configures the default set to accept positional arguments
+// anywhere among the flags. See FlagSet.AllowInterspersedPositionals.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func AllowInterspersedPositionals() error {
+	return CommandLine.AllowInterspersedPositionals()
+}
+
 // SetMandatoryNArgs configures the parser to require exactly N positional arguments.
 // The parser first checks for N arguments before any flags. If not found, it checks
 // for exactly N arguments at the tail end after all flags and flag arguments.
-// This call is mutually exclusive with AllowArbitraryLeadingPositionals. Must be called before defining flags or Parse.
-func SetMandatoryNArgs(n int) error {
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func (fs *FlagSet) SetMandatoryNArgs(n int) error {
 	if n < 0 {
 		return fmt.Errorf("%w: number of mandatory args cannot be negative", ErrConfiguration)
 	}
-	if err := checkPositionalConfigConflict(modeMandatoryN); err != nil {
+	if err := fs.checkPositionalConfigConflict(modeMandatoryN); err != nil {
 		return err
 	}
-	posMode = modeMandatoryN
-	mandatoryN = n
-	slog.Debug("Positional mode set: Mandatory N", "N", n)
+	fs.posMode = modeMandatoryN
+	fs.mandatoryN = n
+	fs.mandatoryMax = n
+	slog.Debug("Positional mode set: Mandatory N", "set", fs.name, "N", n)
 	return nil
 }
 
+// SetMandatoryNArgs configures the default set to require exactly N positional arguments.
+// See FlagSet.SetMandatoryNArgs for the placement rules.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func SetMandatoryNArgs(n int) error {
+	return CommandLine.SetMandatoryNArgs(n)
+}
+
+// SetPositionalRange configures the parser to require between lo and hi positional
+// arguments (hi < 0 means no upper limit), using the same placement rules as SetMandatoryNArgs:
+// the parser first checks for lo..hi arguments before any flags, then at the tail end after
+// all flags and flag arguments. SetPositionalRange(n, n) is equivalent to SetMandatoryNArgs(n).
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func (fs *FlagSet) SetPositionalRange(lo int, hi int) error {
+	if lo < 0 {
+		return fmt.Errorf("%w: minimum number of positional args cannot be negative", ErrConfiguration)
+	}
+	if hi >= 0 && hi < lo {
+		return fmt.Errorf("%w: maximum number of positional args (%d) is less than the minimum (%d)", ErrConfiguration, hi, lo)
+	}
+	if err := fs.checkPositionalConfigConflict(modeMandatoryN); err != nil {
+		return err
+	}
+	if hi < 0 {
+		hi = -1
+	}
+	fs.posMode = modeMandatoryN
+	fs.mandatoryN = lo
+	fs.mandatoryMax = hi
+	slog.Debug("Positional mode set: Positional Range", "set", fs.name, "min", lo, "max", hi)
+	return nil
+}
+
+// SetPositionalRange configures the default set to require between lo and hi positional
+// arguments. See FlagSet.SetPositionalRange for the placement rules.
+// This call is mutually exclusive with the other positional modes. Must be called before defining flags or Parse.
+func SetPositionalRange(lo int, hi int) error {
+	return CommandLine.SetPositionalRange(lo, hi)
+}
+
+// inPositionalRange reports whether n positional arguments satisfy the MandatoryN range.
+func (fs *FlagSet) inPositionalRange(n int) bool {
+	return n >= fs.mandatoryN && (fs.mandatoryMax < 0 || n <= fs.mandatoryMax)
+}
+
+// positionalRangeDesc describes the MandatoryN range, e.g. "exactly 2" or "1 to 3".
+func (fs *FlagSet) positionalRangeDesc() string {
+	switch {
+	case fs.mandatoryMax == fs.mandatoryN:
+		return fmt.Sprintf("exactly %d", fs.mandatoryN)
+	case fs.mandatoryMax < 0:
+		return fmt.Sprintf("at least %d", fs.mandatoryN)
+	default:
+		return fmt.Sprintf("%d to %d", fs.mandatoryN, fs.mandatoryMax)
+	}
+}
+
+// SetReserveTrailingPositionals controls whether a greedy flag that is still consuming at the
+// end of the arguments gives its last N tokens back as the N trailing positionals required by
+// SetMandatoryNArgs (or the minimum of SetPositionalRange). With reserve on, "mycmd -e go mod file1 file2" with N=2 yields
+// ext=[go mod] and Args()=[file1 file2]. Parse fails if reserving would leave the greedy flag
+// with fewer values than it needs (at least one, or its SetGreedyArity minimum).
+func (fs *FlagSet) SetReserveTrailingPositionals(reserve bool) {
+	fs.reserveTrailing = reserve
+}
+
+// SetReserveTrailingPositionals controls trailing positional reservation for the default set.
+// See FlagSet.SetReserveTrailingPositionals.
+func SetReserveTrailingPositionals(reserve bool) {
+	CommandLine.SetReserveTrailingPositionals(reserve)
+}
+
 // --- Parsing Function ---
 
 // Parse parses the command-line arguments from os.Args[1:]. Must be called
 // after all flags and positional requirements are defined and before flags are accessed.
 // Returns ErrHelp if -h or --help was invoked, or another error if parsing/validation fails.
-func Parse() error {
-	if parsed {
+func (fs *FlagSet) Parse() error {
+	fs.argOffset = 1 // Source indexes count from os.Args[0]
+	return fs.ParseArgs(os.Args[1:])
+}
+
+// ParseArgs parses the caller-supplied argument list, which should not include
+// the command name. It runs the same algorithm as Parse and has the same
+// requirements and return values.
+func (fs *FlagSet) ParseArgs(arguments []string) error {
+	if fs.parsed {
 		return fmt.Errorf("%w: Parse() already called", ErrParsing)
 	}
 
 	// Automatically add help flag if not disabled and not already defined
-	if allowHelpFlag {
-		if Lookup("help") == nil {
+	if fs.allowHelpFlag {
+		if fs.Lookup("help") == nil {
 			// Use BoolVarP to potentially add -h as well, but avoid panic if -h exists
 			helpPtr := new(bool)
 			helpFlag := &Flag{
@@ -284,32 +759,53 @@ func Parse() error {
 				DefValue:  "false",
 				IsBool:    true,
 			}
-			if _, exists := flags["help"]; !exists {
-				flags["help"] = helpFlag
+			if _, exists := fs.flags["help"]; !exists {
+				fs.flags["help"] = helpFlag
 				// Only add -h if it's not already taken
-				if _, exists := shortFlags['h']; !exists {
+				if _, exists := fs.shortFlags['h']; !exists {
 					helpFlag.Shorthand = "h"
-					shortFlags['h'] = helpFlag
+					fs.shortFlags['h'] = helpFlag
 				}
 			}
 		}
 	}
 
-	osArgs := os.Args[1:]
-	args = []string{} // Reset global args
+	fs.args = []string{} // Reset positional args
+	fs.applyImpliedPositionals()
 
 	var leadingPositionals []string
+	var interspersedPositionals []string
 	var trailingArgsBuffer []string
 	var activeGreedyFlag *Flag = nil
+	var greedyCount int // Values consumed by the current occurrence of activeGreedyFlag
 	var flagsSeen bool = false
 
+	var pendingGreedy []string // Tokens held back from activeGreedyFlag while trailing positionals may be reserved
+
+	// endGreedy deactivates the active greedy flag, checking it consumed enough values.
+	endGreedy := func() error {
+		f := activeGreedyFlag
+		activeGreedyFlag = nil
+		for _, arg := range pendingGreedy {
+			if err := f.Value.Set(arg); err != nil {
+				return fmt.Errorf("%w: invalid value %q for greedy flag --%s: %v", ErrParsing, arg, f.Name, err)
+			}
+		}
+		pendingGreedy = nil
+		if f != nil && greedyCount < f.MinArgs {
+			return arityError(f, greedyCount)
+		}
+		return nil
+	}
+
 	// --- Pass 1 (Conceptual for MandatoryN Leading Check) ---
 	foundLeadingMandatory := false
-	leadingArgsToProcess := osArgs // Start with all args
-	if posMode == modeMandatoryN && mandatoryN >= 0 {
+	leadingCandidates := 0            // Non-flag tokens before the first flag when they did not satisfy MandatoryN
+	leadingArgsToProcess := arguments // Start with all args
+	if fs.posMode == modeMandatoryN && fs.mandatoryN >= 0 {
 		tempLeading := []string{}
 		firstFlagIndex := -1
-		for i, arg := range osArgs {
+		for i, arg := range arguments {
 			// Basic check for potential flag start
 			if strings.HasPrefix(arg, "-") && len(arg) > 1 && !isNumeric(arg) {
 				firstFlagIndex = i
@@ -318,36 +814,85 @@ func Parse() error {
 			tempLeading = append(tempLeading, arg)
 		}
 
-		// If exactly N args found before any flag OR if N args is all there is
-		if len(tempLeading) == mandatoryN && (firstFlagIndex == mandatoryN || (firstFlagIndex == -1 && len(osArgs) == mandatoryN)) {
-			slog.Debug("Found mandatory N leading positional arguments", "count", mandatoryN, "args", tempLeading)
-			leadingPositionals = tempLeading           // Store them
-			leadingArgsToProcess = osArgs[mandatoryN:] // Process flags after these
+		// If an allowed number of args is found before any flag (tempLeading stops at the first flag).
+		// Zero leading args only count when no positionals are allowed at all, so that a range
+		// with min 0 still lets trailing positionals be found.
+		if fs.inPositionalRange(len(tempLeading)) && (len(tempLeading) > 0 || fs.mandatoryMax == 0) {
+			slog.Debug("Found mandatory N leading positional arguments", "count", len(tempLeading), "args", tempLeading)
+			leadingPositionals = tempLeading                    // Store them
+			leadingArgsToProcess = arguments[len(tempLeading):] // Process flags after these
 			foundLeadingMandatory = true
 			flagsSeen = true // Act as if flags started
 		} else {
-			slog.Debug("Mandatory N leading positional arguments not found/matched", "needed", mandatoryN, "found_before_flag", len(tempLeading))
+			slog.Debug("Mandatory N leading positional arguments not found/matched", "needed", fs.positionalRangeDesc(), "found_before_flag", len(tempLeading), "first_flag_index", firstFlagIndex)
+			leadingCandidates = len(tempLeading)
 			// Will check trailing args later
 		}
 	}
 
 	// --- Pass 2 (Main Parsing Loop) ---
 	i := 0
+	// reserving holds greedy tokens back so the last N can become trailing positionals
+	reserving := fs.reserveTrailing && fs.posMode == modeMandatoryN && !foundLeadingMandatory && fs.mandatoryN > 0
+
+	// cliSource is the source of a flag given by the token just read; the index counts from the
+	// start of arguments (which also holds any leading positionals split off above) plus argOffset.
+	cliSource := func() ValueSource {
+		return ValueSource{Kind: SourceCommandLine, ArgIndex: fs.argOffset + len(arguments) - len(leadingArgsToProcess) + i - 1}
+	}
+
+	// takeRemainder hands every token not yet processed to the remainder flag f.
+	takeRemainder := func(f *Flag) error {
+		for _, arg := range leadingArgsToProcess[i:] {
+			if err := f.Value.Set(arg); err != nil {
+				return fmt.Errorf("%w: invalid value %q for flag --%s: %v", ErrParsing, arg, f.Name, err)
+			}
+		}
+		slog.Debug("Remaining arguments taken by remainder flag", "flag", f.Name, "args", leadingArgsToProcess[i:])
+		i = len(leadingArgsToProcess)
+		return nil
+	}
+
 	for i < len(leadingArgsToProcess) {
 		arg := leadingArgsToProcess[i]
 		i++ // Consume argument for next iteration by default
 
 		slog.Debug("Parsing token", "token", arg, "index", i-1, "greedy_active", activeGreedyFlag != nil)
 
+		// A greedy flag with its own terminator consumes everything up to that terminator
+		if activeGreedyFlag != nil && activeGreedyFlag.Terminator != "" {
+			if arg == activeGreedyFlag.Terminator {
+				slog.Debug("Greedy consumption stopped by flag terminator", "arg", arg, "greedy_flag", activeGreedyFlag.Name)
+				if err := endGreedy(); err != nil {
+					return err
+				}
+				continue
+			}
+			slog.Debug("Consumed by terminated greedy flag", "arg", arg, "greedy_flag", activeGreedyFlag.Name)
+			if err := activeGreedyFlag.Value.Set(arg); err != nil {
+				return fmt.Errorf("%w: invalid value %q for greedy flag --%s: %v", ErrParsing, arg, activeGreedyFlag.Name, err)
+			}
+			activeGreedyFlag.changed = true
+			greedyCount++
+			if activeGreedyFlag.MaxArgs > 0 && greedyCount > activeGreedyFlag.MaxArgs {
+				return arityError(activeGreedyFlag, greedyCount)
+			}
+			continue
+		}
+
 		// Handle terminator first
 		if arg == "--" {
 			slog.Debug("Parsing stopped by terminator '--'")
-			parsingComplete = true
-			activeGreedyFlag = nil
-			// Buffer remaining args only if MandatoryN mode might need them
-			if posMode == modeMandatoryN && !foundLeadingMandatory {
+			if err := endGreedy(); err != nil {
+				return err
+			}
+			// Buffer remaining args only if MandatoryN or trailing mode might need them
+			if (fs.posMode == modeMandatoryN && !foundLeadingMandatory) || fs.posMode == modeArbitraryTrailing {
 				trailingArgsBuffer = append(trailingArgsBuffer, leadingArgsToProcess[i:]...)
 				slog.Debug("Buffering args after -- for potential trailing positionals", "buffered", trailingArgsBuffer)
+			} else if fs.posMode == modeInterspersed {
+				interspersedPositionals = append(interspersedPositionals, leadingArgsToProcess[i:]...)
+				slog.Debug("Collected interspersed positionals after --", "args", leadingArgsToProcess[i:])
 			}
 			break // Stop processing loop
 		}
@@ -355,28 +900,41 @@ func Parse() error {
 		// If a greedy flag is active, try to consume
 		if activeGreedyFlag != nil {
 			// Does current arg look like a flag? (Improved check)
-			isPotentialFlag := strings.HasPrefix(arg, "-") && len(arg) > 1 && !isNumeric(arg)
+			isPotentialFlag := strings.HasPrefix(arg, "-") && len(arg) > 1 && !isNumeric(arg) && !acceptsDashToken(activeGreedyFlag, arg)
 			isPotentialLongFlag := strings.HasPrefix(arg, "--") && len(arg) > 2
 
 			if isPotentialFlag || isPotentialLongFlag {
 				slog.Debug("Greedy consumption stopped by potential flag", "arg", arg, "previous_greedy_flag", activeGreedyFlag.Name)
-				activeGreedyFlag = nil // Stop greedy mode
-				i--                    // Re-process this token as a potential flag
+				if err := endGreedy(); err != nil { // Stop greedy mode
+					return err
+				}
+				i-- // Re-process this token as a potential flag
 				continue
 			} else {
 				// Consume argument for the greedy flag
 				slog.Debug("Consumed by greedy flag", "arg", arg, "greedy_flag", activeGreedyFlag.Name)
-				if err := activeGreedyFlag.Value.Set(arg); err != nil {
-					return fmt.Errorf("%w: error setting greedy flag '%s': %v", ErrParsing, activeGreedyFlag.Name, err)
+				if reserving {
+					// Hold the token back until we know it is not a trailing positional
+					pendingGreedy = append(pendingGreedy, arg)
+				} else if err := activeGreedyFlag.Value.Set(arg); err != nil {
+					return fmt.Errorf("%w: invalid value %q for greedy flag --%s: %v", ErrParsing, arg, activeGreedyFlag.Name, err)
 				}
 				activeGreedyFlag.changed = true
+				greedyCount++
+				if activeGreedyFlag.MaxArgs > 0 && greedyCount >= activeGreedyFlag.MaxArgs {
+					// Hand the remaining tokens back to flag/positional handling
+					slog.Debug("Greedy consumption stopped at maximum arity", "greedy_flag", activeGreedyFlag.Name, "max", activeGreedyFlag.MaxArgs)
+					if err := endGreedy(); err != nil {
+						return err
+					}
+				}
 				continue // Move to next argument
 			}
 		}
 
 		// If not consuming greedy, check if it's a flag
 		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
-			if !flagsSeen && posMode == modeArbitraryLeading {
+			if !flagsSeen && fs.posMode == modeArbitraryLeading {
 				// If we were collecting leading positionals and hit the first flag
 				slog.Debug("First flag encountered, stopping leading positional collection")
 			}
@@ -394,16 +952,30 @@ func Parse() error {
 				}
 
 				// Handle help flag explicitly
-				if name == "help" && allowHelpFlag {
+				if name == "help" && fs.allowHelpFlag {
 					return ErrHelp
 				}
 
-				f := Lookup(name)
+				f := fs.Lookup(name)
 				if f == nil {
+					// --no-<name> turns off a negatable boolean flag
+					if neg := fs.negatedFlag(name); neg != nil {
+						if hasValue {
+							return fmt.Errorf("%w: negated flag --%s does not take a value", ErrParsing, name)
+						}
+						neg.changed = true
+						neg.Source = cliSource()
+						activeGreedyFlag = nil
+						if err := neg.Value.Set("false"); err != nil {
+							return fmt.Errorf("%w: internal error setting boolean flag --%s: %v", ErrParsing, neg.Name, err)
+						}
+						continue
+					}
 					return fmt.Errorf("%w: unknown long flag --%s", ErrParsing, name)
 				}
 
 				f.changed = true
+				f.Source = cliSource()
 				activeGreedyFlag = nil // Deactivate previous greedy
 
 				if f.IsBool {
@@ -416,13 +988,37 @@ func Parse() error {
 							return fmt.Errorf("%w: internal error setting boolean flag --%s: %v", ErrParsing, name, err)
 						}
 					}
+				} else if f.IsCount {
+					if !hasValue {
+						value = countIncrement
+					}
+					if err := f.Value.Set(value); err != nil {
+						return fmt.Errorf("%w: invalid value %q for flag --%s: %v", ErrParsing, value, name, err)
+					}
+				} else if f.IsRemainder {
+					if hasValue {
+						if err := f.Value.Set(value); err != nil {
+							return fmt.Errorf("%w: invalid value %q for flag --%s: %v", ErrParsing, value, name, err)
+						}
+					}
+					if err := takeRemainder(f); err != nil {
+						return err
+					}
 				} else if f.IsGreedy {
 					if hasValue {
 						if err := f.Value.Set(value); err != nil {
-							return fmt.Errorf("%w: error setting greedy flag '%s' with '=': %v", ErrParsing, f.Name, err)
+							return fmt.Errorf("%w: invalid value %q for greedy flag --%s: %v", ErrParsing, value, f.Name, err)
+						}
+						if f.Terminator != "" {
+							// The attached value is the first; consumption still runs to the terminator
+							activeGreedyFlag = f
+							greedyCount = 1
+						} else if f.MinArgs > 1 { // '=' supplies exactly one value
+							return arityError(f, 1)
 						}
 					} else {
 						activeGreedyFlag = f
+						greedyCount = 0
 						slog.Debug("Greedy mode activated", "flag", f.Name)
 					}
 				} else { // Standard flag expecting value
@@ -430,8 +1026,13 @@ func Parse() error {
 						if err := f.Value.Set(value); err != nil {
 							return fmt.Errorf("%w: invalid value %q for flag --%s: %v", ErrParsing, value, name, err)
 						}
+					} else if f.NoOptDefVal != "" {
+						// Optional value: a bare flag never takes the next token
+						if err := f.Value.Set(f.NoOptDefVal); err != nil {
+							return fmt.Errorf("%w: invalid value %q for flag --%s: %v", ErrParsing, f.NoOptDefVal, name, err)
+						}
 					} else {
-						if i >= len(leadingArgsToProcess) || (strings.HasPrefix(leadingArgsToProcess[i], "-") && !isNumeric(leadingArgsToProcess[i])) || leadingArgsToProcess[i] == "--" {
+						if i >= len(leadingArgsToProcess) || !canBeValue(f, leadingArgsToProcess[i]) {
 							return fmt.Errorf("%w: flag needs an argument: --%s", ErrParsing, name)
 						}
 						value = leadingArgsToProcess[i]
@@ -448,10 +1049,17 @@ func Parse() error {
 			namePart := arg[1:]     // Part after '-'
 			if len(namePart) == 0 { // Just "-"
 				// Treat as potential trailing positional if mode B and flags seen, else error
-				if posMode == modeMandatoryN && flagsSeen && !foundLeadingMandatory {
+				if fs.posMode == modeMandatoryN && flagsSeen && !foundLeadingMandatory {
 					trailingArgsBuffer = append(trailingArgsBuffer, arg)
 					slog.Debug("Buffering potential trailing positional", "arg", arg)
-				} else if posMode == modeArbitraryLeading && !flagsSeen {
+				} else if fs.posMode == modeInterspersed {
+					interspersedPositionals = append(interspersedPositionals, arg)
+					slog.Debug("Collected interspersed positional", "arg", arg)
+				} else if fs.posMode == modeArbitraryTrailing {
+					trailingArgsBuffer = append(trailingArgsBuffer, leadingArgsToProcess[i-1:]...)
+					slog.Debug("Collected trailing positionals", "args", trailingArgsBuffer)
+					break
+				} else if fs.posMode == modeArbitraryLeading && !flagsSeen {
 					leadingPositionals = append(leadingPositionals, arg)
 					slog.Debug("Collected leading positional", "arg", arg)
 				} else {
@@ -472,11 +1080,12 @@ func Parse() error {
 				}
 
 				shorthandRune := rune(shortName[0])
-				f := shortFlags[shorthandRune]
+				f := fs.shortFlags[shorthandRune]
 				if f == nil {
 					return fmt.Errorf("%w: unknown short flag -%s", ErrParsing, shortName)
 				}
 				f.changed = true
+				f.Source = cliSource()
 				activeGreedyFlag = nil
 
 				if f.IsBool {
@@ -485,7 +1094,21 @@ func Parse() error {
 				if err := f.Value.Set(value); err != nil {
 					return fmt.Errorf("%w: invalid value %q for flag -%s: %v", ErrParsing, value, shortName, err)
 				}
+				if f.IsGreedy && f.Terminator != "" {
+					// The attached value is the first; consumption still runs to the terminator
+					activeGreedyFlag = f
+					greedyCount = 1
+					continue
+				}
 				// Note: Greedy flags with '=' don't activate greedy mode
+				if f.IsGreedy && f.MinArgs > 1 {
+					return arityError(f, 1)
+				}
+				if f.IsRemainder {
+					if err := takeRemainder(f); err != nil {
+						return err
+					}
+				}
 				continue
 			}
 
@@ -493,33 +1116,40 @@ func Parse() error {
 			activeGreedyFlag = nil // Deactivate previous greedy before processing short flags
 			for j, r := range namePart {
 				isLastChar := (j == len(namePart)-1)
-				f := shortFlags[r]
+				f := fs.shortFlags[r]
 				if f == nil {
 					// Check for help flag explicitly
-					if namePart == "h" && allowHelpFlag {
+					if namePart == "h" && fs.allowHelpFlag {
 						return ErrHelp
 					}
 					return fmt.Errorf("%w: unknown flag in short flags: -%c (in %s)", ErrParsing, r, arg)
 				}
 				f.changed = true
+				f.Source = cliSource()
 
-				if !isLastChar { // Characters before the last must be booleans
-					if !f.IsBool {
+				bareValue, canBeBare := noArgValue(f)
+				if !isLastChar { // Characters before the last must not need an argument (booleans, counters -vvv, optional values)
+					if !canBeBare {
 						return fmt.Errorf("%w: flag -%c requires value, cannot be combined before end in %s", ErrParsing, r, arg)
 					}
-					if err := f.Value.Set("true"); err != nil {
-						return fmt.Errorf("%w: internal error setting boolean flag -%c: %v", ErrParsing, r, err)
+					if err := f.Value.Set(bareValue); err != nil {
+						return fmt.Errorf("%w: invalid value %q for flag -%c: %v", ErrParsing, bareValue, r, err)
 					}
 				} else { // Last character in the group (or only character)
-					if f.IsBool {
-						if err := f.Value.Set("true"); err != nil {
-							return fmt.Errorf("%w: internal error setting boolean flag -%c: %v", ErrParsing, r, err)
+					if canBeBare {
+						if err := f.Value.Set(bareValue); err != nil {
+							return fmt.Errorf("%w: invalid value %q for flag -%c: %v", ErrParsing, bareValue, r, err)
+						}
+					} else if f.IsRemainder {
+						if err := takeRemainder(f); err != nil {
+							return err
 						}
 					} else if f.IsGreedy {
 						activeGreedyFlag = f // Activate greedy mode for subsequent args
+						greedyCount = 0
 						slog.Debug("Greedy mode activated", "flag", f.Name)
 					} else { // Standard flag expecting value
-						if i >= len(leadingArgsToProcess) || (strings.HasPrefix(leadingArgsToProcess[i], "-") && !isNumeric(leadingArgsToProcess[i])) || leadingArgsToProcess[i] == "--" {
+						if i >= len(leadingArgsToProcess) || !canBeValue(f, leadingArgsToProcess[i]) {
 							return fmt.Errorf("%w: flag needs an argument: -%c (in %s)", ErrParsing, r, arg)
 						}
 						value := leadingArgsToProcess[i]
@@ -534,13 +1164,21 @@ func Parse() error {
 		} // End flag handling
 
 		// --- Handle Non-Flag Token ---
-		if posMode == modeArbitraryLeading && !flagsSeen {
+		if fs.posMode == modeArbitraryLeading && !flagsSeen {
 			leadingPositionals = append(leadingPositionals, arg)
 			slog.Debug("Collected leading positional", "arg", arg)
-		} else if posMode == modeMandatoryN && !foundLeadingMandatory {
-			// Buffer non-flags seen after flags start if leading N weren't found
+		} else if fs.posMode == modeMandatoryN {
+			// Buffer non-flags seen after flags start; validated against the leading ones later
 			trailingArgsBuffer = append(trailingArgsBuffer, arg)
 			slog.Debug("Buffering potential trailing positional", "arg", arg)
+		} else if fs.posMode == modeInterspersed {
+			interspersedPositionals = append(interspersedPositionals, arg)
+			slog.Debug("Collected interspersed positional", "arg", arg)
+		} else if fs.posMode == modeArbitraryTrailing {
+			// The first positional ends flag parsing; everything from here on is positional
+			trailingArgsBuffer = append(trailingArgsBuffer, leadingArgsToProcess[i-1:]...)
+			slog.Debug("Collected trailing positionals", "args", trailingArgsBuffer)
+			break
 		} else {
 			// Error: Unexpected non-flag argument based on mode
 			// (e.g., default mode, or arbitrary mode after flags seen, or mandatory N mode after leading found)
@@ -548,12 +1186,37 @@ func Parse() error {
 		}
 
 	} // End argument loop
+	if activeGreedyFlag != nil && activeGreedyFlag.Terminator != "" {
+		return fmt.Errorf("%w: flag --%s: missing terminator %q", ErrParsing, activeGreedyFlag.Name, activeGreedyFlag.Terminator)
+	}
+	if reserving && activeGreedyFlag != nil && len(trailingArgsBuffer) == 0 {
+		// The greedy flag ran to the end of the arguments: its last N tokens are the trailing positionals
+		keep := len(pendingGreedy) - fs.mandatoryN
+		if keep < max(1, activeGreedyFlag.MinArgs) {
+			return fmt.Errorf("%w: ambiguous arguments %v: reserving %d trailing positional arguments would leave greedy flag --%s with %d value(s)", ErrValidation, pendingGreedy, fs.mandatoryN, activeGreedyFlag.Name, max(keep, 0))
+		}
+		trailingArgsBuffer = append(trailingArgsBuffer, pendingGreedy[keep:]...)
+		pendingGreedy = pendingGreedy[:keep]
+		greedyCount = keep
+		slog.Debug("Reserved trailing positionals from greedy flag", "greedy_flag", activeGreedyFlag.Name, "args", trailingArgsBuffer)
+	}
+	if err := endGreedy(); err != nil {
+		return err
+	}
+
+	// Flags not given on the command line fall back to the environment, then the config file
+	if err := fs.applyEnv(); err != nil {
+		return err
+	}
+	if err := fs.applyConfig(); err != nil {
+		return err
+	}
 
 	// --- Final Positional Argument Validation ---
-	parsed = true
+	fs.parsed = true
 	finalPositionals := []string{}
 
-	switch posMode {
+	switch fs.posMode {
 	case modeArbitraryLeading:
 		if len(trailingArgsBuffer) > 0 {
 			return fmt.Errorf("%w: non-flag arguments found after flags when arbitrary leading positionals expected: %v", ErrValidation, trailingArgsBuffer)
@@ -564,19 +1227,28 @@ func Parse() error {
 	case modeMandatoryN:
 		if foundLeadingMandatory { // N args were found before flags
 			if len(trailingArgsBuffer) > 0 {
-				return fmt.Errorf("%w: non-flag arguments found after flags when %d leading positionals were already found", ErrValidation, mandatoryN)
+				return fmt.Errorf("%w: expected %s positional arguments all before the first flag or all after the flags, found %d before and %d after: %v", ErrValidation, fs.positionalRangeDesc(), len(leadingPositionals), len(trailingArgsBuffer), trailingArgsBuffer)
 			}
 			finalPositionals = leadingPositionals // Use the ones found earlier
-			slog.Debug("Validation: Mandatory N Leading Positionals", "required", mandatoryN, "found", len(finalPositionals), "args", finalPositionals)
-			// Already checked count == mandatoryN when setting foundLeadingMandatory
+			slog.Debug("Validation: Mandatory N Leading Positionals", "required", fs.positionalRangeDesc(), "found", len(finalPositionals), "args", finalPositionals)
+			// Already checked the count is in range when setting foundLeadingMandatory
 		} else { // N args were NOT found before flags, check trailing buffer
-			if len(trailingArgsBuffer) != mandatoryN {
-				return fmt.Errorf("%w: expected exactly %d trailing positional arguments, found %d: %v", ErrValidation, mandatoryN, len(trailingArgsBuffer), trailingArgsBuffer)
+			if !fs.inPositionalRange(len(trailingArgsBuffer)) {
+				// Tokens before the first flag were buffered along with the trailing ones
+				return fmt.Errorf("%w: expected %s positional arguments before the first flag or after the flags, found %d before and %d after: %v", ErrValidation, fs.positionalRangeDesc(), leadingCandidates, len(trailingArgsBuffer)-leadingCandidates, trailingArgsBuffer)
 			}
 			finalPositionals = trailingArgsBuffer
-			slog.Debug("Validation: Mandatory N Trailing Positionals", "required", mandatoryN, "found", len(finalPositionals), "args", finalPositionals)
+			slog.Debug("Validation: Mandatory N Trailing Positionals", "required", fs.positionalRangeDesc(), "found", len(finalPositionals), "args", finalPositionals)
 		}
 
+	case modeArbitraryTrailing:
+		finalPositionals = trailingArgsBuffer
+		slog.Debug("Validation: Arbitrary Trailing Positionals", "count", len(finalPositionals), "args", finalPositionals)
+
+	case modeInterspersed:
+		finalPositionals = interspersedPositionals
+		slog.Debug("Validation: Interspersed Positionals", "count", len(finalPositionals), "args", finalPositionals)
+
 	case modeNone:
 		// leadingPositionals should be empty by definition if modeNone
 		if len(trailingArgsBuffer) > 0 {
@@ -585,122 +1257,205 @@ func Parse() error {
 		slog.Debug("Validation: No positional arguments allowed or found.")
 	}
 
-	// Assign final positionals to global state
-	args = finalPositionals
+	// Assign final positionals to the set
+	fs.args = finalPositionals
+	if err := fs.bindPositionals(); err != nil {
+		return err
+	}
 
 	// Check if help was requested during parsing
-	helpFlag := Lookup("help")
-	if allowHelpFlag && helpFlag != nil && helpFlag.changed {
+	helpFlag := fs.Lookup("help")
+	if fs.allowHelpFlag && helpFlag != nil && helpFlag.changed {
 		return ErrHelp
 	}
 
 	return nil // Success
 }
 
+// Parse parses the command-line arguments from os.Args[1:] into the default set.
+// Must be called after all flags and positional requirements are defined and before flags are accessed.
+// Returns ErrHelp if -h or --help was invoked, or another error if parsing/validation fails.
+func Parse() error {
+	return CommandLine.Parse()
+}
+
+// ParseArgs parses the given argument list (without the command name) into the default set.
+// See FlagSet.ParseArgs.
+func ParseArgs(arguments []string) error {
+	return CommandLine.ParseArgs(arguments)
+}
+
 // --- Result Access Functions ---
 
-// Args returns the non-flag command-line arguments based on the configured mode.
-func Args() []string {
-	if !parsed {
-		fmt.Fprintln(os.Stderr, "Warning: Args() called before Parse()") // Or return error?
+// Args returns the non-flag arguments based on the configured mode.
+func (fs *FlagSet) Args() []string {
+	if !fs.parsed {
+		fmt.Fprintln(fs.Output(), "Warning: Args() called before Parse()") // Or return error?
 		return []string{}
 	}
 	// Return a copy to prevent modification? For now, return direct slice.
-	return args
+	return fs.args
 }
 
-// NArg returns the number of non-flag command-line arguments found.
-func NArg() int {
-	if !parsed {
-		fmt.Fprintln(os.Stderr, "Warning: NArg() called before Parse()")
+// Args returns the non-flag command-line arguments based on the configured mode.
+func Args() []string {
+	return CommandLine.Args()
+}
+
+// NArg returns the number of non-flag arguments found.
+func (fs *FlagSet) NArg() int {
+	if !fs.parsed {
+		fmt.Fprintln(fs.Output(), "Warning: NArg() called before Parse()")
 		return 0
 	}
-	return len(args)
+	return len(fs.args)
+}
+
+// NArg returns the number of non-flag command-line arguments found.
+func NArg() int {
+	return CommandLine.NArg()
 }
 
 // Lookup returns the Flag structure for the defined flag name (long name).
+func (fs *FlagSet) Lookup(name string) *Flag {
+	return fs.flags[name] // Returns nil if not found
+}
+
+// Lookup returns the Flag structure for the defined command-line flag name (long name).
 func Lookup(name string) *Flag {
-	return flags[name] // Returns nil if not found
+	return CommandLine.Lookup(name)
 }
 
-// Visit visits the command-line flags that were set, calling fn for each.
-// It visits only those flags specified on the command line.
-func Visit(fn func(*Flag)) {
-	if !parsed {
-		fmt.Fprintln(os.Stderr, "Warning: Visit() called before Parse()")
-		return
-	}
+// sortedFlags returns the flags of the set in lexicographical order of their long names.
+func (fs *FlagSet) sortedFlags() []*Flag {
 	// Need deterministic order
-	names := make([]string, 0, len(flags))
-	for name := range flags {
+	names := make([]string, 0, len(fs.flags))
+	for name := range fs.flags {
 		names = append(names, name)
 	}
 	sort.Strings(names)
-	for _, name := range names {
-		f := flags[name]
+	result := make([]*Flag, len(names))
+	for i, name := range names {
+		result[i] = fs.flags[name]
+	}
+	return result
+}
+
+// Visit visits the flags that were set, calling fn for each.
+// It visits only those flags set on the command line, in the environment or in a config
+// file; check Flag.Source to tell them apart.
+func (fs *FlagSet) Visit(fn func(*Flag)) {
+	if !fs.parsed {
+		fmt.Fprintln(fs.Output(), "Warning: Visit() called before Parse()")
+		return
+	}
+	for _, f := range fs.sortedFlags() {
 		if f.changed {
 			fn(f)
 		}
 	}
 }
 
+// Visit visits the command-line flags that were set, calling fn for each.
+// It visits only those flags set on the command line, in the environment or in a config file.
+func Visit(fn func(*Flag)) {
+	CommandLine.Visit(fn)
+}
+
+// VisitAll visits all defined flags, calling fn for each.
+func (fs *FlagSet) VisitAll(fn func(*Flag)) {
+	for _, f := range fs.sortedFlags() {
+		fn(f)
+	}
+}
+
 // VisitAll visits all defined command-line flags, calling fn for each.
 func VisitAll(fn func(*Flag)) {
-	// Need deterministic order
-	names := make([]string, 0, len(flags))
-	for name := range flags {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	for _, name := range names {
-		fn(flags[name])
-	}
+	CommandLine.VisitAll(fn)
 }
 
 // --- Help/Usage ---
 
-// Usage can be overridden by the user. The default prints a usage message.
-// It is called by Parse() upon error or when help is requested.
-var Usage = defaultUsage
+// Usage can be overridden by the user. The default prints a usage message
+// for CommandLine. It is called by Parse() upon error or when help is requested.
+var Usage = func() {
+	CommandLine.defaultUsage()
+}
 
-// defaultUsage prints a usage message documenting all defined command-line flags to os.Stderr.
-func defaultUsage() {
+// defaultUsage prints a usage message documenting all defined flags to the set's output.
+func (fs *FlagSet) defaultUsage() {
 	// Generate the top usage line based on configuration
-	usageLine := fmt.Sprintf("Usage: %s", os.Args[0])
-	hasFlags := len(flags) > 0
+	progName := fs.name
+	if fs.cmd != nil {
+		progName = fs.cmd.CommandPath()
+	}
+	usageLine := fmt.Sprintf("Usage: %s", progName)
+	mode, lo, hi := fs.effectivePositionals()
+	hasFlags := len(fs.flags) > 0
 	posDesc := ""
 
-	switch posMode {
+	switch mode {
 	case modeArbitraryLeading:
-		posDesc = "[pos_args...]"
+		posDesc = fs.declaredPositionalsDesc() + "[pos_args...]"
 		if hasFlags {
 			usageLine += " " + posDesc + " [flags]"
 		} else {
 			usageLine += " " + posDesc
 		}
 	case modeMandatoryN:
-		argsList := make([]string, mandatoryN)
-		for i := 0; i < mandatoryN; i++ {
-			argsList[i] = fmt.Sprintf("<arg%d>", i+1)
+		argsList := make([]string, lo)
+		for i := 0; i < lo; i++ {
+			argsList[i] = "<" + fs.positionalName(i) + ">"
+		}
+		for i := lo; i < hi; i++ {
+			argsList = append(argsList, "["+fs.positionalName(i)+"]")
+		}
+		if hi < 0 {
+			argsList = append(argsList, "[args...]")
 		}
 		posDesc = strings.Join(argsList, " ")
 		// Show both forms as possible usage patterns
-		usageLine += fmt.Sprintf(" %s [flags]\n   or: %s [flags] %s", posDesc, os.Args[0], posDesc)
+		usageLine += fmt.Sprintf(" %s [flags]\n   or: %s [flags] %s", posDesc, progName, posDesc)
+	case modeArbitraryTrailing:
+		if hasFlags {
+			usageLine += " [flags]"
+		}
+		usageLine += " " + fs.declaredPositionalsDesc() + "[args...]"
+	case modeInterspersed:
+		if hasFlags {
+			usageLine += " " + fs.declaredPositionalsDesc() + "[flags and args...]"
+		} else {
+			usageLine += " " + fs.declaredPositionalsDesc() + "[args...]"
+		}
 	case modeNone:
 		if hasFlags {
 			usageLine += " [flags]"
 		}
 	}
-	fmt.Fprintln(os.Stderr, usageLine)
+	if fs.cmd != nil && len(fs.cmd.commands) > 0 {
+		// Commands without Run can only be used through a subcommand
+		commandLine := fmt.Sprintf("%s <command> [args...]", progName)
+		if fs.cmd.Run == nil {
+			usageLine = "Usage: " + commandLine
+		} else {
+			usageLine += "\n   or: " + commandLine
+		}
+	}
+	fmt.Fprintln(fs.Output(), usageLine)
+
+	// List subcommands, if any
+	fs.printCommands()
 
 	// Print flag defaults
-	PrintDefaults()
+	fs.PrintDefaults()
 }
 
-// PrintDefaults prints, to standard error, a usage message documenting all defined command-line flags.
-func PrintDefaults() {
-	fmt.Fprintf(os.Stderr, "\nFlags:\n")
-	VisitAll(func(f *Flag) {
+// PrintDefaults prints, to the set's output, a usage message documenting all defined flags.
+func (fs *FlagSet) PrintDefaults() {
+	fs.printPositionals()
+	out := fs.Output()
+	fmt.Fprintf(out, "\nFlags:\n")
+	fs.VisitAll(func(f *Flag) {
 		line := "  "
 		// Format short/long name part
 		short := ""
@@ -708,6 +1463,9 @@ func PrintDefaults() {
 			short = fmt.Sprintf("-%s", f.Shorthand)
 		}
 		long := fmt.Sprintf("--%s", f.Name)
+		if f.Negatable {
+			long = fmt.Sprintf("--[no-]%s", f.Name)
+		}
 
 		if short != "" {
 			line += fmt.Sprintf("%s, %s", short, long)
@@ -719,8 +1477,10 @@ func PrintDefaults() {
 		// Add type/value indicator
 		typeName, hasArgument := flagType(f)
 		if hasArgument {
-			if f.IsGreedy {
+			if f.IsGreedy || f.IsRemainder {
 				line += fmt.Sprintf(" %s...", typeName) // Indicate greedy repetition
+			} else if f.NoOptDefVal != "" {
+				line += fmt.Sprintf(" %s[=%s]", typeName, f.NoOptDefVal) // Value is optional
 			} else {
 				line += fmt.Sprintf(" %s", typeName)
 			}
@@ -744,54 +1504,124 @@ func PrintDefaults() {
 		if !f.IsBool && f.DefValue != "" && f.DefValue != "[]" && f.DefValue != "false" && f.DefValue != "0" {
 			line += fmt.Sprintf(" (default %s)", f.DefValue)
 		}
+		if f.IsCount {
+			line += " (repeatable)"
+		}
+		if f.IsRemainder {
+			line += " (takes all remaining arguments)"
+		}
+		if f.IsGreedy && (f.MinArgs > 0 || f.MaxArgs > 0) {
+			line += fmt.Sprintf(" (%s)", arityDesc(f))
+		}
+		if f.IsGreedy && f.Terminator != "" {
+			line += fmt.Sprintf(" (ends at %q)", f.Terminator)
+		}
+		if envVars := fs.envVarsFor(f); len(envVars) > 0 {
+			line += " [$" + strings.Join(envVars, ", $") + "]"
+		}
 		// Add greedy indicator (optional, already in type name)
 		// if f.IsGreedy { line += " (greedy)" }
 
-		fmt.Fprintln(os.Stderr, line)
+		fmt.Fprintln(out, line)
 	})
 }
 
+// PrintDefaults prints, to standard error, a usage message documenting all defined command-line flags.
+func PrintDefaults() {
+	CommandLine.PrintDefaults()
+}
+
 // flagType is a helper for PrintDefaults to guess the type name. Needs improvement for non-builtins.
 func flagType(f *Flag) (name string, hasArgument bool) {
-	if f.IsBool {
-		return "", false // Booleans don't typically show a type name
+	if f.IsBool || f.IsCount {
+		return "", false // Booleans and counters don't take an argument
 	}
 	hasArgument = true // Assume others take arguments
+	if f.IsRemainder {
+		return "arg", true
+	}
 	// Basic type guessing
 	switch f.Value.(type) {
 	case *stringValue:
 		name = "string"
+	case *boolValue:
+		name = "bool" // Only reached for positionals; boolean flags return above
 	case *stringSliceValue:
 		name = "string" // Base type is string, PrintDefaults adds "..."
-	// case *intValue: name = "int" // Add if implementing int
+	case *intValue:
+		name = "int"
+	case *int64Value:
+		name = "int64"
+	case *uintValue:
+		name = "uint"
+	case *uint64Value:
+		name = "uint64"
+	case *float64Value:
+		name = "float64"
+	case *sliceValue[int]:
+		name = "int"
+	case *sliceValue[int64]:
+		name = "int64"
+	case *sliceValue[uint]:
+		name = "uint"
+	case *sliceValue[float64]:
+		name = "float64"
+	case *sliceValue[time.Duration], *durationValue:
+		name = "duration"
+	case *sliceValue[time.Time], *timeValue:
+		name = "time"
+	case *sliceValue[DateRange], *dateRangeValue:
+		name = "dateRange"
 	default:
 		name = "value" // Generic placeholder
+		if t, ok := f.Value.(typeNamer); ok && t.typeName() != "" {
+			name = t.typeName()
+		}
 	}
 	return
 }
 
-// isNumeric checks if a string is purely numeric (potentially negative).
-func isNumeric(s string) bool {
-	if s == "" {
+// noArgValue returns the value passed to Set when f is given without an argument,
+// and whether f may be given without one at all.
+func noArgValue(f *Flag) (string, bool) {
+	switch {
+	case f.IsCount:
+		return countIncrement, true
+	case f.IsBool:
+		return "true", true
+	case f.NoOptDefVal != "" && !f.IsGreedy:
+		return f.NoOptDefVal, true
+	}
+	return "", false
+}
+
+// canBeValue reports whether next, the token following a flag that requires a value,
+// can be consumed as that value rather than being the start of another flag.
+func canBeValue(f *Flag, next string) bool {
+	if next == "--" {
 		return false
 	}
+	return !strings.HasPrefix(next, "-") || isNumeric(next) || acceptsDashToken(f, next)
+}
+
+// isNumeric checks if a string is a number, potentially negative (e.g. "-7", "-0.5",
+// "-1e3", "-0x1f"), so that negative values are not mistaken for flags.
+func isNumeric(s string) bool {
 	// Allow negative sign only at the start
-	if s[0] == '-' {
-		if len(s) == 1 {
-			return false
-		} // Just "-" is not numeric
-		s = s[1:]
-		if s == "" {
-			return false
-		} // Just "-" is not numeric
-	}
-	// Check remaining characters are digits
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			return false
-		}
-	}
-	return true
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" { // Empty or just "-" is not numeric
+		return false
+	}
+	// Require a leading digit or decimal point to rule out "inf", "nan" etc.
+	if c := digits[0]; (c < '0' || c > '9') && c != '.' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(digits, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+		return true
+	}
+	// Integer forms ParseFloat rejects (octal, binary, underscores)
+	_, err := strconv.ParseInt(digits, 0, 64)
+	return err == nil || errors.Is(err, strconv.ErrRange)
 }
 
 // Helper to get map keys for logging set contents
@@ -0,0 +1,590 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// specFlags is the flag set used by the examples in greedyflag-spec.rst §7.
+type specFlags struct {
+	fs         *FlagSet
+	verbose    *bool
+	logfile    *string
+	output     *string
+	extensions *[]string
+	exclude    *[]string
+}
+
+// newSpecFlags returns a fresh set configured by posConfig (may be nil).
+func newSpecFlags(t *testing.T, posConfig func(*FlagSet) error) *specFlags {
+	t.Helper()
+	fs := NewFlagSet("mycmd")
+	fs.SetOutput(&strings.Builder{})
+	if posConfig != nil {
+		if err := posConfig(fs); err != nil {
+			t.Fatalf("configuring positionals: %v", err)
+		}
+	}
+	return &specFlags{
+		fs:         fs,
+		verbose:    fs.BoolP("verbose", "v", false, "Enable verbose output"),
+		logfile:    fs.StringP("logfile", "l", "", "Log file"),
+		output:     fs.StringP("output", "o", "", "Output file"),
+		extensions: fs.StringSliceGreedyP("extensions", "e", nil, "Extensions to include (greedy)"),
+		exclude:    fs.StringSliceGreedyP("exclude", "x", nil, "Patterns to exclude (greedy)"),
+	}
+}
+
+func mandatoryN(n int) func(*FlagSet) error {
+	return func(fs *FlagSet) error { return fs.SetMandatoryNArgs(n) }
+}
+
+func TestParseArgsSpecExamples(t *testing.T) {
+	tests := []struct {
+		name       string
+		posConfig  func(*FlagSet) error
+		args       []string
+		verbose    bool
+		logfile    string
+		output     string
+		extensions []string
+		exclude    []string
+		positional []string
+	}{
+		{
+			name:       "terminator before trailing positionals",
+			posConfig:  mandatoryN(2),
+			args:       []string{"-v", "--logfile", "/tmp/log.txt", "-e", "go", "mod", "py", "--", "main.go", "data/"},
+			verbose:    true,
+			logfile:    "/tmp/log.txt",
+			extensions: []string{"go", "mod", "py"},
+			positional: []string{"main.go", "data/"},
+		},
+		{
+			name:       "greedy stopped by next flag",
+			args:       []string{"--exclude", "*.tmp", "*.log", "-o", "output.txt"},
+			output:     "output.txt",
+			exclude:    []string{"*.tmp", "*.log"},
+			positional: []string{},
+		},
+		{
+			name:       "equals form takes one value",
+			posConfig:  mandatoryN(1),
+			args:       []string{"-e=go", "-e", "py", "--", "file.txt"},
+			extensions: []string{"go", "py"},
+			positional: []string{"file.txt"},
+		},
+		{
+			name:       "consecutive greedy flags",
+			posConfig:  mandatoryN(1),
+			args:       []string{"-e", "go", "py", "-x", "*.tmp", "data", "--", "file.txt"},
+			extensions: []string{"go", "py"},
+			exclude:    []string{"*.tmp", "data"},
+			positional: []string{"file.txt"},
+		},
+		{
+			name:       "combined short flags ending in greedy",
+			args:       []string{"-ve", "go", "mod"},
+			verbose:    true,
+			extensions: []string{"go", "mod"},
+			positional: []string{},
+		},
+		{
+			name:       "arbitrary leading positionals",
+			posConfig:  (*FlagSet).AllowArbitraryLeadingPositionals,
+			args:       []string{"file1", "file2", "-v", "-e", "go", "mod"},
+			verbose:    true,
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+		{
+			name: "reserved trailing positionals",
+			posConfig: func(fs *FlagSet) error {
+				fs.SetReserveTrailingPositionals(true)
+				return fs.SetMandatoryNArgs(2)
+			},
+			args:       []string{"-v", "-e", "go", "mod", "file1", "file2"},
+			verbose:    true,
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+		{
+			// The spec lists this as an error; see the note in §7
+			name:       "greedy flag consumes a would-be positional",
+			args:       []string{"-e", "go", "py", "file1.txt"},
+			extensions: []string{"go", "py", "file1.txt"},
+			positional: []string{},
+		},
+		{
+			name:       "mandatory leading positionals",
+			posConfig:  mandatoryN(2),
+			args:       []string{"file1", "file2", "-v", "-e", "go", "mod"},
+			verbose:    true,
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, tt.posConfig)
+			if err := f.fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if *f.verbose != tt.verbose {
+				t.Errorf("verbose = %v, want %v", *f.verbose, tt.verbose)
+			}
+			if *f.logfile != tt.logfile {
+				t.Errorf("logfile = %q, want %q", *f.logfile, tt.logfile)
+			}
+			if *f.output != tt.output {
+				t.Errorf("output = %q, want %q", *f.output, tt.output)
+			}
+			if want := orEmpty(tt.extensions); !reflect.DeepEqual(*f.extensions, want) {
+				t.Errorf("extensions = %q, want %q", *f.extensions, want)
+			}
+			if want := orEmpty(tt.exclude); !reflect.DeepEqual(*f.exclude, want) {
+				t.Errorf("exclude = %q, want %q", *f.exclude, want)
+			}
+			if got := f.fs.Args(); !reflect.DeepEqual(got, tt.positional) {
+				t.Errorf("Args() = %q, want %q", got, tt.positional)
+			}
+		})
+	}
+}
+
+func TestParseArgsSpecErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		posConfig func(*FlagSet) error
+		args      []string
+		wantErr   error
+	}{
+		{"one leading positional", mandatoryN(2), []string{"file1", "-v", "-e", "go", "mod"}, ErrValidation},
+		{"one trailing positional", mandatoryN(2), []string{"-e", "go", "mod", "file1"}, ErrValidation},
+		{"greedy flag consumes trailing positionals", mandatoryN(2), []string{"-e", "1", "2"}, ErrValidation},
+		// The spec's "unintuitive case" expects success; see the note in §7 on why it fails
+		{"positional between flags", mandatoryN(2), []string{"-v", "file1", "-e", "go", "mod", "file2"}, ErrValidation},
+		{"positional in default mode", nil, []string{"file1.txt", "-e", "go", "py"}, ErrParsing},
+		{"positional after flags in leading mode", (*FlagSet).AllowArbitraryLeadingPositionals, []string{"-v", "file1.txt"}, ErrParsing},
+		{"unknown flag", nil, []string{"--nope"}, ErrParsing},
+		{"value flag without value", nil, []string{"-o"}, ErrParsing},
+		{"value flag before end of short group", nil, []string{"-ov", "x"}, ErrParsing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, tt.posConfig)
+			err := f.fs.ParseArgs(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseArgs(%q) error = %v, want %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseArgsHelp(t *testing.T) {
+	for _, args := range [][]string{{"--help"}, {"-h"}, {"-v", "--help"}} {
+		f := newSpecFlags(t, nil)
+		if err := f.fs.ParseArgs(args); !errors.Is(err, ErrHelp) {
+			t.Errorf("ParseArgs(%q) error = %v, want ErrHelp", args, err)
+		}
+	}
+}
+
+func TestParseArgsTwice(t *testing.T) {
+	f := newSpecFlags(t, nil)
+	if err := f.fs.ParseArgs(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.fs.ParseArgs(nil); !errors.Is(err, ErrParsing) {
+		t.Fatalf("second ParseArgs error = %v, want ErrParsing", err)
+	}
+}
+
+// orEmpty returns s, or an empty non-nil slice if s is nil (greedy values are never nil).
+func orEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// reserveN configures MandatoryN with trailing positional reservation.
+func reserveN(n int) func(*FlagSet) error {
+	return func(fs *FlagSet) error {
+		fs.SetReserveTrailingPositionals(true)
+		return fs.SetMandatoryNArgs(n)
+	}
+}
+
+func TestReserveTrailingPositionals(t *testing.T) {
+	tests := []struct {
+		name       string
+		posConfig  func(*FlagSet) error
+		arity      []int // Optional min, max for --extensions
+		args       []string
+		extensions []string
+		exclude    []string
+		positional []string
+	}{
+		{
+			name:       "last N greedy tokens reserved",
+			posConfig:  reserveN(2),
+			args:       []string{"-e", "go", "mod", "file1", "file2"},
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+		{
+			name:       "only the last greedy flag gives tokens back",
+			posConfig:  reserveN(1),
+			args:       []string{"-x", "a", "b", "-e", "go", "file1"},
+			extensions: []string{"go"},
+			exclude:    []string{"a", "b"},
+			positional: []string{"file1"},
+		},
+		{
+			name:       "terminator ends the greedy flag without reserving",
+			posConfig:  reserveN(2),
+			args:       []string{"-e", "go", "mod", "file1", "--", "a", "b"},
+			extensions: []string{"go", "mod", "file1"},
+			positional: []string{"a", "b"},
+		},
+		{
+			name:       "maximum arity ends the greedy flag without reserving",
+			posConfig:  reserveN(2),
+			arity:      []int{1, 2},
+			args:       []string{"-e", "go", "mod", "file1", "file2"},
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+		{
+			name:       "leading positionals disable reservation",
+			posConfig:  reserveN(2),
+			args:       []string{"file1", "file2", "-e", "go", "mod"},
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1", "file2"},
+		},
+		{
+			name: "range minimum is reserved",
+			posConfig: func(fs *FlagSet) error {
+				fs.SetReserveTrailingPositionals(true)
+				return fs.SetPositionalRange(1, 3)
+			},
+			args:       []string{"-e", "go", "mod", "file1"},
+			extensions: []string{"go", "mod"},
+			positional: []string{"file1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, tt.posConfig)
+			if tt.arity != nil {
+				if err := f.fs.SetGreedyArity("extensions", tt.arity[0], tt.arity[1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := f.fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if want := orEmpty(tt.extensions); !reflect.DeepEqual(*f.extensions, want) {
+				t.Errorf("extensions = %q, want %q", *f.extensions, want)
+			}
+			if want := orEmpty(tt.exclude); !reflect.DeepEqual(*f.exclude, want) {
+				t.Errorf("exclude = %q, want %q", *f.exclude, want)
+			}
+			if got := f.fs.Args(); !reflect.DeepEqual(got, tt.positional) {
+				t.Errorf("Args() = %q, want %q", got, tt.positional)
+			}
+		})
+	}
+}
+
+func TestReserveTrailingPositionalsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		minArgs int
+		wantErr error
+		wantMsg string
+	}{
+		{"nothing left for the greedy flag", []string{"-e", "1", "2"}, 0, ErrValidation, "ambiguous arguments"},
+		{"below the greedy minimum", []string{"-e", "go", "mod", "f1", "f2"}, 3, ErrValidation, "would leave greedy flag --extensions with 2 value(s)"},
+		{"no trailing positionals", []string{"-e", "go"}, 0, ErrValidation, "ambiguous arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, reserveN(2))
+			if tt.minArgs > 0 {
+				if err := f.fs.SetGreedyArity("extensions", tt.minArgs, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := f.fs.ParseArgs(tt.args)
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ParseArgs(%q) error = %v, want %v containing %q", tt.args, err, tt.wantErr, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestReserveTrailingPositionalsTypedValues(t *testing.T) {
+	newSet := func() (*FlagSet, *[]int) {
+		fs := NewFlagSet("mycmd")
+		fs.SetReserveTrailingPositionals(true)
+		if err := fs.SetMandatoryNArgs(2); err != nil {
+			t.Fatal(err)
+		}
+		return fs, fs.IntSliceGreedyP("nums", "n", nil, "Numbers")
+	}
+
+	// Held-back tokens are only converted once they are known to belong to the flag
+	fs, nums := newSet()
+	if err := fs.ParseArgs([]string{"-n", "1", "2", "a.txt", "b.txt"}); err != nil {
+		t.Fatalf("ParseArgs: %v", err)
+	}
+	if !reflect.DeepEqual(*nums, []int{1, 2}) || !reflect.DeepEqual(fs.Args(), []string{"a.txt", "b.txt"}) {
+		t.Errorf("nums = %v, Args() = %q; want [1 2], [a.txt b.txt]", *nums, fs.Args())
+	}
+
+	// A bad element inside the held-back run is still a parse error naming the token
+	fs, _ = newSet()
+	err := fs.ParseArgs([]string{"-n", "1", "x", "3", "a.txt", "b.txt"})
+	if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), `invalid value "x" for greedy flag --nums`) {
+		t.Fatalf("ParseArgs error = %v, want ErrParsing for \"x\"", err)
+	}
+
+	// So is one held back from a greedy flag that a later flag ended
+	fs, _ = newSet()
+	fs.BoolP("verbose", "v", false, "Verbose")
+	err = fs.ParseArgs([]string{"-n", "1", "x", "-v", "--", "a.txt", "b.txt"})
+	if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), `invalid value "x"`) {
+		t.Fatalf("ParseArgs error = %v, want ErrParsing for \"x\"", err)
+	}
+}
+
+func TestGreedyArity(t *testing.T) {
+	tests := []struct {
+		name       string
+		posConfig  func(*FlagSet) error
+		lo, hi     int
+		args       []string
+		extensions []string
+		verbose    bool
+		positional []string
+	}{
+		{"exact N hands extra tokens to trailing positionals", mandatoryN(2), 2, 2, []string{"-e", "go", "py", "a", "b"}, []string{"go", "py"}, false, []string{"a", "b"}},
+		{"exact N hands extra tokens to interspersed positionals", (*FlagSet).AllowInterspersedPositionals, 1, 1, []string{"-e", "go", "a", "-v", "b"}, []string{"go"}, true, []string{"a", "b"}},
+		{"maximum stops before a flag anyway", nil, 1, 3, []string{"-e", "go", "-v"}, []string{"go"}, true, []string{}},
+		{"repeated occurrences count separately", nil, 1, 1, []string{"-e", "go", "-e", "py"}, []string{"go", "py"}, false, []string{}},
+		{"attached value satisfies a minimum of one", nil, 1, 0, []string{"--extensions=go"}, []string{"go"}, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, tt.posConfig)
+			if err := f.fs.SetGreedyArity("extensions", tt.lo, tt.hi); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(*f.extensions, tt.extensions) || *f.verbose != tt.verbose || !reflect.DeepEqual(orEmpty(f.fs.Args()), tt.positional) {
+				t.Errorf("extensions = %q, verbose = %v, Args() = %q; want %q, %v, %q", *f.extensions, *f.verbose, f.fs.Args(), tt.extensions, tt.verbose, tt.positional)
+			}
+		})
+	}
+}
+
+func TestGreedyArityErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		lo, hi  int
+		args    []string
+		wantMsg string
+	}{
+		{"no values under a minimum of one", 1, 0, []string{"-e"}, "flag --extensions expects at least 1 value(s), got 0"},
+		{"no values before the next flag", 1, 0, []string{"-e", "-v"}, "expects at least 1 value(s), got 0"},
+		{"long attached value under a minimum of two", 2, 0, []string{"--extensions=go", "py"}, "expects at least 2 value(s), got 1"},
+		{"short attached value under a minimum of two", 2, 2, []string{"-e=go"}, "expects 2 value(s), got 1"},
+		{"too few for exact N", 2, 2, []string{"-e", "go"}, "expects 2 value(s), got 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, nil)
+			if err := f.fs.SetGreedyArity("extensions", tt.lo, tt.hi); err != nil {
+				t.Fatal(err)
+			}
+			err := f.fs.ParseArgs(tt.args)
+			if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ParseArgs(%q) error = %v, want ErrParsing containing %q", tt.args, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestGreedyArityConfiguration(t *testing.T) {
+	f := newSpecFlags(t, nil)
+	for _, err := range []error{
+		f.fs.SetGreedyArity("nope", 1, 2),
+		f.fs.SetGreedyArity("output", 1, 2),
+		f.fs.SetGreedyArity("extensions", -1, 2),
+		f.fs.SetGreedyArity("extensions", 3, 2),
+		f.fs.SetGreedyNArgs("extensions", 0),
+	} {
+		if !errors.Is(err, ErrConfiguration) {
+			t.Errorf("error = %v, want ErrConfiguration", err)
+		}
+	}
+	if err := f.fs.SetGreedyNArgs("extensions", 2); err != nil {
+		t.Fatal(err)
+	}
+	if fl := f.fs.Lookup("extensions"); fl.MinArgs != 2 || fl.MaxArgs != 2 {
+		t.Errorf("MinArgs, MaxArgs = %d, %d; want 2, 2", fl.MinArgs, fl.MaxArgs)
+	}
+}
+
+func TestNegatableFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"--no-cache"}, false},
+		{[]string{"--cache"}, true},
+		{[]string{"--no-cache", "--cache"}, true},
+		{[]string{"--cache", "--no-cache"}, false},
+	}
+	for _, tt := range tests {
+		fs := NewFlagSet("t")
+		cache := fs.BoolP("cache", "c", true, "Use the cache")
+		if err := fs.SetNegatable("cache"); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.ParseArgs(tt.args); err != nil {
+			t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+		}
+		if *cache != tt.want {
+			t.Errorf("ParseArgs(%q): cache = %v, want %v", tt.args, *cache, tt.want)
+		}
+	}
+}
+
+func TestNegatableFlagErrors(t *testing.T) {
+	newSet := func() *FlagSet {
+		fs := NewFlagSet("t")
+		fs.SetOutput(&strings.Builder{})
+		fs.BoolP("cache", "c", true, "Use the cache")
+		fs.StringP("name", "n", "", "Name")
+		return fs
+	}
+
+	fs := newSet()
+	if err := fs.SetNegatable("cache"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ParseArgs([]string{"--no-cache=true"}); !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), "negated flag --no-cache does not take a value") {
+		t.Errorf("--no-cache=true error = %v, want ErrParsing", err)
+	}
+
+	fs = newSet()
+	if err := fs.ParseArgs([]string{"--no-cache"}); !errors.Is(err, ErrParsing) {
+		t.Errorf("--no-cache without SetNegatable error = %v, want ErrParsing", err)
+	}
+
+	fs = newSet()
+	for _, name := range []string{"nope", "name"} {
+		if err := fs.SetNegatable(name); !errors.Is(err, ErrConfiguration) {
+			t.Errorf("SetNegatable(%q) error = %v, want ErrConfiguration", name, err)
+		}
+	}
+	fs.BoolP("no-cache", "", false, "Skip the cache")
+	if err := fs.SetNegatable("cache"); !errors.Is(err, ErrConfiguration) || !strings.Contains(err.Error(), "--no-cache is already defined") {
+		t.Errorf("SetNegatable with existing --no-cache error = %v, want ErrConfiguration", err)
+	}
+
+	fs = newSet()
+	if err := fs.SetNegatable("cache"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "negation of --cache") {
+			t.Errorf("recover() = %v, want a negation redefinition panic", r)
+		}
+	}()
+	fs.BoolP("no-cache", "", false, "Skip the cache")
+}
+
+func TestNegatableFlagHelp(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.BoolP("cache", "c", true, "Use the cache")
+	if err := fs.SetNegatable("cache"); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	if !strings.Contains(out.String(), "-c, --[no-]cache") {
+		t.Errorf("PrintDefaults output %q does not show --[no-]cache", out.String())
+	}
+}
+
+func TestNoOptDefVal(t *testing.T) {
+	tests := []struct {
+		args       []string
+		color      string
+		verbose    bool
+		positional []string
+	}{
+		{nil, "never", false, []string{}},
+		{[]string{"--color"}, "auto", false, []string{}},
+		{[]string{"--color", "always"}, "auto", false, []string{"always"}},
+		{[]string{"-c", "always"}, "auto", false, []string{"always"}},
+		{[]string{"--color=always"}, "always", false, []string{}},
+		{[]string{"-c=always"}, "always", false, []string{}},
+		{[]string{"-cv", "x"}, "auto", true, []string{"x"}},
+		{[]string{"-vc"}, "auto", true, []string{}},
+	}
+	for _, tt := range tests {
+		fs := NewFlagSet("t")
+		if err := fs.AllowArbitraryTrailingPositionals(); err != nil {
+			t.Fatal(err)
+		}
+		color := fs.StringP("color", "c", "never", "When to color output")
+		verbose := fs.BoolP("verbose", "v", false, "Verbose")
+		if err := fs.SetNoOptDefVal("color", "auto"); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.ParseArgs(tt.args); err != nil {
+			t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+		}
+		if *color != tt.color || *verbose != tt.verbose || !reflect.DeepEqual(orEmpty(fs.Args()), tt.positional) {
+			t.Errorf("ParseArgs(%q): color = %q, verbose = %v, Args() = %q; want %q, %v, %q", tt.args, *color, *verbose, fs.Args(), tt.color, tt.verbose, tt.positional)
+		}
+	}
+}
+
+func TestNoOptDefValConfiguration(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.StringP("color", "c", "never", "When to color output")
+	fs.BoolP("verbose", "v", false, "Verbose")
+	fs.CountP("debug", "d", "Debug level")
+	fs.StringSliceGreedyP("extensions", "e", nil, "Extensions")
+	fs.RemainderP("cmd", "", "Command")
+	for _, name := range []string{"nope", "verbose", "debug", "extensions", "cmd"} {
+		if err := fs.SetNoOptDefVal(name, "x"); !errors.Is(err, ErrConfiguration) {
+			t.Errorf("SetNoOptDefVal(%q) error = %v, want ErrConfiguration", name, err)
+		}
+	}
+	if err := fs.SetNoOptDefVal("color", ""); !errors.Is(err, ErrConfiguration) {
+		t.Errorf("SetNoOptDefVal with empty value error = %v, want ErrConfiguration", err)
+	}
+	if err := fs.SetNoOptDefVal("color", "auto"); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	if !strings.Contains(out.String(), "--color string[=auto]") {
+		t.Errorf("PrintDefaults output %q does not show the optional value", out.String())
+	}
+}
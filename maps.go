@@ -0,0 +1,207 @@
+package greedyflag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// --- Key=Value Map Value Types ---
+
+// DuplicateKeyPolicy controls how a map flag treats a key given more than once.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLastWins keeps the value of the last occurrence of a key (the default).
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeyError reports a repeated key as a parse error.
+	DuplicateKeyError
+)
+
+// -- mapValue -- (Used for Greedy Flags)
+type mapValue[V any] struct {
+	p      *map[string]V
+	parse  func(string) (V, error) // Converts the part after '='
+	format func(V) string          // Renders one value for help output
+	policy DuplicateKeyPolicy
+	seen   map[string]bool // Keys set by Set, for DuplicateKeyError
+	typ    string          // Type name shown by PrintDefaults
+}
+
+func newMapValue[V any](val map[string]V, p *map[string]V, parse func(string) (V, error), format func(V) string, typ string) *mapValue[V] {
+	// Copy the default so that Set never modifies the caller's map
+	m := make(map[string]V, len(val))
+	for k, v := range val {
+		m[k] = v
+	}
+	*p = m
+	return &mapValue[V]{p: p, parse: parse, format: format, seen: make(map[string]bool), typ: typ}
+}
+func (m *mapValue[V]) Set(s string) error {
+	// Split on the first '=' only, so values may themselves contain '='
+	key, raw, found := strings.Cut(s, "=")
+	if !found {
+		return fmt.Errorf("invalid key=value pair %q (missing '=')", s)
+	}
+	if key == "" {
+		return fmt.Errorf("invalid key=value pair %q (empty key)", s)
+	}
+	if m.policy == DuplicateKeyError && m.seen[key] {
+		return fmt.Errorf("duplicate key %q", key)
+	}
+	v, err := m.parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for key %q: %v", key, err)
+	}
+	(*m.p)[key] = v
+	m.seen[key] = true
+	return nil
+}
+func (m *mapValue[V]) String() string {
+	if m.p == nil || len(*m.p) == 0 {
+		return "[]"
+	}
+	keys := make([]string, 0, len(*m.p))
+	for k := range *m.p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + m.format((*m.p)[k])
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+func (m *mapValue[V]) typeName() string { return m.typ }
+func (m *mapValue[V]) reset() {
+	*m.p = make(map[string]V)
+	m.seen = make(map[string]bool)
+}
+func (m *mapValue[V]) setDuplicateKeyPolicy(policy DuplicateKeyPolicy) {
+	m.policy = policy
+}
+
+// mapValuer is implemented by key=value map values.
+type mapValuer interface {
+	setDuplicateKeyPolicy(policy DuplicateKeyPolicy)
+}
+
+// addMapFlag defines a greedy flag backed by a mapValue. Internal use.
+func addMapFlag[V any](fs *FlagSet, p *map[string]V, name string, shorthand string, value map[string]V, usage string, parse func(string) (V, error), format func(V) string, typ string) {
+	v := newMapValue(value, p, parse, format, typ)
+	// Store default value representation for help message
+	defValStr := newMapValue(value, new(map[string]V), parse, format, typ).String()
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  defValStr,
+		IsGreedy:  true,
+	})
+}
+
+// --- Map Flag Definition Functions ---
+
+// GreedyMapVar defines a greedy map[string]V flag in fs with specified name, shorthand, default
+// value, and usage string. Each consumed token is split on its first '=' into a key and a value,
+// and the value is converted with parse. The argument p points to a map[string]V variable in which
+// to store the values of the flag. Use CommandLine as fs for the default set.
+func GreedyMapVar[V any](fs *FlagSet, p *map[string]V, name string, shorthand string, value map[string]V, usage string, parse func(string) (V, error)) {
+	addMapFlag(fs, p, name, shorthand, value, usage, parse, formatValue[V], "key="+typeNameOf[V]())
+}
+
+// StringToStringGreedyVarP defines a greedy map[string]string flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must have the form key=value; tokens are split on the first '='.
+// The argument p points to a map[string]string variable in which to store the values of the flag.
+func (fs *FlagSet) StringToStringGreedyVarP(p *map[string]string, name string, shorthand string, value map[string]string, usage string) {
+	addMapFlag(fs, p, name, shorthand, value, usage, func(s string) (string, error) { return s, nil }, formatString, "key=value")
+}
+
+// StringToStringGreedyVarP defines a greedy map[string]string flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a map[string]string variable in which to store the values of the flag.
+func StringToStringGreedyVarP(p *map[string]string, name string, shorthand string, value map[string]string, usage string) {
+	CommandLine.StringToStringGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// StringToStringGreedyP is like StringToStringGreedyVarP, but returns a pointer to a map[string]string variable.
+func (fs *FlagSet) StringToStringGreedyP(name string, shorthand string, value map[string]string, usage string) *map[string]string {
+	p := new(map[string]string)
+	fs.StringToStringGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// StringToStringGreedyP is like StringToStringGreedyVarP, but returns a pointer to a map[string]string variable.
+func StringToStringGreedyP(name string, shorthand string, value map[string]string, usage string) *map[string]string {
+	return CommandLine.StringToStringGreedyP(name, shorthand, value, usage)
+}
+
+// StringToIntGreedyVarP defines a greedy map[string]int flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must have the form key=value; tokens are split on the first '='.
+// The argument p points to a map[string]int variable in which to store the values of the flag.
+func (fs *FlagSet) StringToIntGreedyVarP(p *map[string]int, name string, shorthand string, value map[string]int, usage string) {
+	addMapFlag(fs, p, name, shorthand, value, usage, parseInt, formatInt, "key=int")
+}
+
+// StringToIntGreedyVarP defines a greedy map[string]int flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a map[string]int variable in which to store the values of the flag.
+func StringToIntGreedyVarP(p *map[string]int, name string, shorthand string, value map[string]int, usage string) {
+	CommandLine.StringToIntGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// StringToIntGreedyP is like StringToIntGreedyVarP, but returns a pointer to a map[string]int variable.
+func (fs *FlagSet) StringToIntGreedyP(name string, shorthand string, value map[string]int, usage string) *map[string]int {
+	p := new(map[string]int)
+	fs.StringToIntGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// StringToIntGreedyP is like StringToIntGreedyVarP, but returns a pointer to a map[string]int variable.
+func StringToIntGreedyP(name string, shorthand string, value map[string]int, usage string) *map[string]int {
+	return CommandLine.StringToIntGreedyP(name, shorthand, value, usage)
+}
+
+// StringToFloat64GreedyVarP defines a greedy map[string]float64 flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must have the form key=value; tokens are split on the first '='.
+// The argument p points to a map[string]float64 variable in which to store the values of the flag.
+func (fs *FlagSet) StringToFloat64GreedyVarP(p *map[string]float64, name string, shorthand string, value map[string]float64, usage string) {
+	addMapFlag(fs, p, name, shorthand, value, usage, parseFloat64, formatFloat64, "key=float64")
+}
+
+// StringToFloat64GreedyVarP defines a greedy map[string]float64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a map[string]float64 variable in which to store the values of the flag.
+func StringToFloat64GreedyVarP(p *map[string]float64, name string, shorthand string, value map[string]float64, usage string) {
+	CommandLine.StringToFloat64GreedyVarP(p, name, shorthand, value, usage)
+}
+
+// StringToFloat64GreedyP is like StringToFloat64GreedyVarP, but returns a pointer to a map[string]float64 variable.
+func (fs *FlagSet) StringToFloat64GreedyP(name string, shorthand string, value map[string]float64, usage string) *map[string]float64 {
+	p := new(map[string]float64)
+	fs.StringToFloat64GreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// StringToFloat64GreedyP is like StringToFloat64GreedyVarP, but returns a pointer to a map[string]float64 variable.
+func StringToFloat64GreedyP(name string, shorthand string, value map[string]float64, usage string) *map[string]float64 {
+	return CommandLine.StringToFloat64GreedyP(name, shorthand, value, usage)
+}
+
+// SetDuplicateKeyPolicy sets how the named map flag treats a key given more than once on the
+// command line. Returns ErrConfiguration if the flag does not exist or is not a map flag.
+func (fs *FlagSet) SetDuplicateKeyPolicy(name string, policy DuplicateKeyPolicy) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: unknown flag --%s", ErrConfiguration, name)
+	}
+	m, ok := f.Value.(mapValuer)
+	if !ok {
+		return fmt.Errorf("%w: flag --%s is not a key=value map flag", ErrConfiguration, name)
+	}
+	m.setDuplicateKeyPolicy(policy)
+	return nil
+}
+
+// SetDuplicateKeyPolicy sets how the named map flag of the default set treats a repeated key.
+func SetDuplicateKeyPolicy(name string, policy DuplicateKeyPolicy) error {
+	return CommandLine.SetDuplicateKeyPolicy(name, policy)
+}
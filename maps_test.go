@@ -0,0 +1,96 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMapFlags(t *testing.T) {
+	fs := NewFlagSet("t")
+	labels := fs.StringToStringGreedyP("label", "l", map[string]string{"team": "core"}, "Labels")
+	limits := fs.StringToIntGreedyP("limit", "n", nil, "Limits")
+	weights := fs.StringToFloat64GreedyP("weight", "w", nil, "Weights")
+	var timeouts map[string]time.Duration
+	GreedyMapVar(fs, &timeouts, "timeout", "t", nil, "Timeouts", time.ParseDuration)
+	args := []string{
+		"-l", "env=prod", "query=a=b", "empty=",
+		"-n", "cpu=2", "mem=-1",
+		"-w", "a=0.5", "b=1e3",
+		"-t", "read=5s",
+		"--label=env=dev",
+	}
+	if err := fs.ParseArgs(args); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"team": "core", "env": "dev", "query": "a=b", "empty": ""}; !reflect.DeepEqual(*labels, want) {
+		t.Errorf("label = %v, want %v", *labels, want)
+	}
+	if want := map[string]int{"cpu": 2, "mem": -1}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("limit = %v, want %v", *limits, want)
+	}
+	if want := map[string]float64{"a": 0.5, "b": 1000}; !reflect.DeepEqual(*weights, want) {
+		t.Errorf("weight = %v, want %v", *weights, want)
+	}
+	if want := map[string]time.Duration{"read": 5 * time.Second}; !reflect.DeepEqual(timeouts, want) {
+		t.Errorf("timeout = %v, want %v", timeouts, want)
+	}
+	if got := fs.Lookup("label").Value.String(); got != "[empty=,env=dev,query=a=b,team=core]" {
+		t.Errorf("label String() = %q", got)
+	}
+}
+
+func TestMapFlagErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantMsg string
+	}{
+		{"missing =", []string{"-l", "env"}, `invalid key=value pair "env" (missing '=')`},
+		{"empty key", []string{"-l", "=prod"}, `invalid key=value pair "=prod" (empty key)`},
+		{"bad int", []string{"-n", "cpu=two"}, `invalid value for key "cpu"`},
+		{"bad float", []string{"-w", "a=heavy"}, `invalid value for key "a"`},
+		{"duplicate key", []string{"-d", "a=1", "b=2", "-d", "a=3"}, `duplicate key "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("t")
+			fs.StringToStringGreedyP("label", "l", nil, "Labels")
+			fs.StringToIntGreedyP("limit", "n", nil, "Limits")
+			fs.StringToFloat64GreedyP("weight", "w", nil, "Weights")
+			fs.StringToStringGreedyP("define", "d", map[string]string{"a": "0"}, "Definitions")
+			if err := fs.SetDuplicateKeyPolicy("define", DuplicateKeyError); err != nil {
+				t.Fatal(err)
+			}
+			err := fs.ParseArgs(tt.args)
+			if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ParseArgs(%q) error = %v, want ErrParsing containing %q", tt.args, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestMapFlagDuplicateKeys(t *testing.T) {
+	fs := NewFlagSet("t")
+	defines := fs.StringToStringGreedyP("define", "d", map[string]string{"a": "0"}, "Definitions")
+	if err := fs.SetDuplicateKeyPolicy("define", DuplicateKeyError); err != nil {
+		t.Fatal(err)
+	}
+	// Overriding a default key is not a duplicate
+	if err := fs.ParseArgs([]string{"-d", "a=1", "b=2"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "1", "b": "2"}; !reflect.DeepEqual(*defines, want) {
+		t.Errorf("define = %v, want %v", *defines, want)
+	}
+
+	fs = NewFlagSet("t")
+	fs.StringP("name", "n", "", "Name")
+	for _, name := range []string{"nope", "name"} {
+		if err := fs.SetDuplicateKeyPolicy(name, DuplicateKeyError); !errors.Is(err, ErrConfiguration) {
+			t.Errorf("SetDuplicateKeyPolicy(%q) error = %v, want ErrConfiguration", name, err)
+		}
+	}
+}
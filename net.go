@@ -0,0 +1,252 @@
+package greedyflag
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+)
+
+// --- Network Value Parsers ---
+
+func parseAddr(s string) (netip.Addr, error) {
+	v, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q", s)
+	}
+	return v, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	v, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR prefix %q", s)
+	}
+	return v, nil
+}
+
+func parseAddrPort(s string) (netip.AddrPort, error) {
+	v, err := netip.ParseAddrPort(s)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("invalid address %q (expected ip:port)", s)
+	}
+	return v, nil
+}
+
+// parseURL accepts absolute URLs only (e.g. "http://host/path"), so that
+// typos such as a missing scheme are reported instead of silently accepted.
+func parseURL(s string) (*url.URL, error) {
+	v, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q", s)
+	}
+	if !v.IsAbs() {
+		return nil, fmt.Errorf("invalid URL %q (expected scheme://...)", s)
+	}
+	return v, nil
+}
+
+// --- Network Flag Definition Functions ---
+
+// AddrVarP defines a flag holding an IP address (netip.Addr) with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.Addr variable in which to store the value of the flag.
+func (fs *FlagSet) AddrVarP(p *netip.Addr, name string, shorthand string, value netip.Addr, usage string) {
+	v := addGenericFlag(fs, p, name, shorthand, value, usage, parseAddr)
+	v.typ = "ip"
+}
+
+// AddrVarP defines a flag holding an IP address (netip.Addr) with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.Addr variable in which to store the value of the flag.
+func AddrVarP(p *netip.Addr, name string, shorthand string, value netip.Addr, usage string) {
+	CommandLine.AddrVarP(p, name, shorthand, value, usage)
+}
+
+// AddrP is like AddrVarP, but returns a pointer to a netip.Addr variable.
+func (fs *FlagSet) AddrP(name string, shorthand string, value netip.Addr, usage string) *netip.Addr {
+	p := new(netip.Addr)
+	fs.AddrVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// AddrP is like AddrVarP, but returns a pointer to a netip.Addr variable.
+func AddrP(name string, shorthand string, value netip.Addr, usage string) *netip.Addr {
+	return CommandLine.AddrP(name, shorthand, value, usage)
+}
+
+// PrefixVarP defines a flag holding a CIDR prefix (netip.Prefix), e.g. 10.0.0.0/8 with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.Prefix variable in which to store the value of the flag.
+func (fs *FlagSet) PrefixVarP(p *netip.Prefix, name string, shorthand string, value netip.Prefix, usage string) {
+	v := addGenericFlag(fs, p, name, shorthand, value, usage, parsePrefix)
+	v.typ = "cidr"
+}
+
+// PrefixVarP defines a flag holding a CIDR prefix (netip.Prefix), e.g. 10.0.0.0/8 with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.Prefix variable in which to store the value of the flag.
+func PrefixVarP(p *netip.Prefix, name string, shorthand string, value netip.Prefix, usage string) {
+	CommandLine.PrefixVarP(p, name, shorthand, value, usage)
+}
+
+// PrefixP is like PrefixVarP, but returns a pointer to a netip.Prefix variable.
+func (fs *FlagSet) PrefixP(name string, shorthand string, value netip.Prefix, usage string) *netip.Prefix {
+	p := new(netip.Prefix)
+	fs.PrefixVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// PrefixP is like PrefixVarP, but returns a pointer to a netip.Prefix variable.
+func PrefixP(name string, shorthand string, value netip.Prefix, usage string) *netip.Prefix {
+	return CommandLine.PrefixP(name, shorthand, value, usage)
+}
+
+// AddrPortVarP defines a flag holding an ip:port pair (netip.AddrPort) with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.AddrPort variable in which to store the value of the flag.
+func (fs *FlagSet) AddrPortVarP(p *netip.AddrPort, name string, shorthand string, value netip.AddrPort, usage string) {
+	v := addGenericFlag(fs, p, name, shorthand, value, usage, parseAddrPort)
+	v.typ = "ip:port"
+}
+
+// AddrPortVarP defines a flag holding an ip:port pair (netip.AddrPort) with specified name, shorthand, default value, and usage string.
+// The argument p points to a netip.AddrPort variable in which to store the value of the flag.
+func AddrPortVarP(p *netip.AddrPort, name string, shorthand string, value netip.AddrPort, usage string) {
+	CommandLine.AddrPortVarP(p, name, shorthand, value, usage)
+}
+
+// AddrPortP is like AddrPortVarP, but returns a pointer to a netip.AddrPort variable.
+func (fs *FlagSet) AddrPortP(name string, shorthand string, value netip.AddrPort, usage string) *netip.AddrPort {
+	p := new(netip.AddrPort)
+	fs.AddrPortVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// AddrPortP is like AddrPortVarP, but returns a pointer to a netip.AddrPort variable.
+func AddrPortP(name string, shorthand string, value netip.AddrPort, usage string) *netip.AddrPort {
+	return CommandLine.AddrPortP(name, shorthand, value, usage)
+}
+
+// URLVarP defines a flag holding an absolute URL with specified name, shorthand, default value, and usage string.
+// The argument p points to a *url.URL variable in which to store the value of the flag.
+func (fs *FlagSet) URLVarP(p **url.URL, name string, shorthand string, value *url.URL, usage string) {
+	v := addGenericFlag(fs, p, name, shorthand, value, usage, parseURL)
+	v.typ = "url"
+}
+
+// URLVarP defines a flag holding an absolute URL with specified name, shorthand, default value, and usage string.
+// The argument p points to a *url.URL variable in which to store the value of the flag.
+func URLVarP(p **url.URL, name string, shorthand string, value *url.URL, usage string) {
+	CommandLine.URLVarP(p, name, shorthand, value, usage)
+}
+
+// URLP is like URLVarP, but returns a pointer to a *url.URL variable.
+func (fs *FlagSet) URLP(name string, shorthand string, value *url.URL, usage string) **url.URL {
+	p := new(*url.URL)
+	fs.URLVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// URLP is like URLVarP, but returns a pointer to a *url.URL variable.
+func URLP(name string, shorthand string, value *url.URL, usage string) **url.URL {
+	return CommandLine.URLP(name, shorthand, value, usage)
+}
+
+// AddrSliceGreedyVarP defines a greedy []netip.Addr flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must be an IP address (netip.Addr).
+// The argument p points to a []netip.Addr variable in which to store the values of the flag.
+func (fs *FlagSet) AddrSliceGreedyVarP(p *[]netip.Addr, name string, shorthand string, value []netip.Addr, usage string) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parseAddr, formatValue[netip.Addr])
+	v.typ = "ip"
+}
+
+// AddrSliceGreedyVarP defines a greedy []netip.Addr flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []netip.Addr variable in which to store the values of the flag.
+func AddrSliceGreedyVarP(p *[]netip.Addr, name string, shorthand string, value []netip.Addr, usage string) {
+	CommandLine.AddrSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// AddrSliceGreedyP is like AddrSliceGreedyVarP, but returns a pointer to a []netip.Addr variable.
+func (fs *FlagSet) AddrSliceGreedyP(name string, shorthand string, value []netip.Addr, usage string) *[]netip.Addr {
+	p := new([]netip.Addr)
+	fs.AddrSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// AddrSliceGreedyP is like AddrSliceGreedyVarP, but returns a pointer to a []netip.Addr variable.
+func AddrSliceGreedyP(name string, shorthand string, value []netip.Addr, usage string) *[]netip.Addr {
+	return CommandLine.AddrSliceGreedyP(name, shorthand, value, usage)
+}
+
+// PrefixSliceGreedyVarP defines a greedy []netip.Prefix flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must be a CIDR prefix (netip.Prefix), e.g. 10.0.0.0/8.
+// The argument p points to a []netip.Prefix variable in which to store the values of the flag.
+func (fs *FlagSet) PrefixSliceGreedyVarP(p *[]netip.Prefix, name string, shorthand string, value []netip.Prefix, usage string) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parsePrefix, formatValue[netip.Prefix])
+	v.typ = "cidr"
+}
+
+// PrefixSliceGreedyVarP defines a greedy []netip.Prefix flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []netip.Prefix variable in which to store the values of the flag.
+func PrefixSliceGreedyVarP(p *[]netip.Prefix, name string, shorthand string, value []netip.Prefix, usage string) {
+	CommandLine.PrefixSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// PrefixSliceGreedyP is like PrefixSliceGreedyVarP, but returns a pointer to a []netip.Prefix variable.
+func (fs *FlagSet) PrefixSliceGreedyP(name string, shorthand string, value []netip.Prefix, usage string) *[]netip.Prefix {
+	p := new([]netip.Prefix)
+	fs.PrefixSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// PrefixSliceGreedyP is like PrefixSliceGreedyVarP, but returns a pointer to a []netip.Prefix variable.
+func PrefixSliceGreedyP(name string, shorthand string, value []netip.Prefix, usage string) *[]netip.Prefix {
+	return CommandLine.PrefixSliceGreedyP(name, shorthand, value, usage)
+}
+
+// AddrPortSliceGreedyVarP defines a greedy []netip.AddrPort flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must be an ip:port pair (netip.AddrPort).
+// The argument p points to a []netip.AddrPort variable in which to store the values of the flag.
+func (fs *FlagSet) AddrPortSliceGreedyVarP(p *[]netip.AddrPort, name string, shorthand string, value []netip.AddrPort, usage string) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parseAddrPort, formatValue[netip.AddrPort])
+	v.typ = "ip:port"
+}
+
+// AddrPortSliceGreedyVarP defines a greedy []netip.AddrPort flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []netip.AddrPort variable in which to store the values of the flag.
+func AddrPortSliceGreedyVarP(p *[]netip.AddrPort, name string, shorthand string, value []netip.AddrPort, usage string) {
+	CommandLine.AddrPortSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// AddrPortSliceGreedyP is like AddrPortSliceGreedyVarP, but returns a pointer to a []netip.AddrPort variable.
+func (fs *FlagSet) AddrPortSliceGreedyP(name string, shorthand string, value []netip.AddrPort, usage string) *[]netip.AddrPort {
+	p := new([]netip.AddrPort)
+	fs.AddrPortSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// AddrPortSliceGreedyP is like AddrPortSliceGreedyVarP, but returns a pointer to a []netip.AddrPort variable.
+func AddrPortSliceGreedyP(name string, shorthand string, value []netip.AddrPort, usage string) *[]netip.AddrPort {
+	return CommandLine.AddrPortSliceGreedyP(name, shorthand, value, usage)
+}
+
+// URLSliceGreedyVarP defines a greedy []*url.URL flag with specified name, shorthand, default value, and usage string.
+// Each consumed token must be an absolute URL.
+// The argument p points to a []*url.URL variable in which to store the values of the flag.
+func (fs *FlagSet) URLSliceGreedyVarP(p *[]*url.URL, name string, shorthand string, value []*url.URL, usage string) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parseURL, formatValue[*url.URL])
+	v.typ = "url"
+}
+
+// URLSliceGreedyVarP defines a greedy []*url.URL flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []*url.URL variable in which to store the values of the flag.
+func URLSliceGreedyVarP(p *[]*url.URL, name string, shorthand string, value []*url.URL, usage string) {
+	CommandLine.URLSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// URLSliceGreedyP is like URLSliceGreedyVarP, but returns a pointer to a []*url.URL variable.
+func (fs *FlagSet) URLSliceGreedyP(name string, shorthand string, value []*url.URL, usage string) *[]*url.URL {
+	p := new([]*url.URL)
+	fs.URLSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// URLSliceGreedyP is like URLSliceGreedyVarP, but returns a pointer to a []*url.URL variable.
+func URLSliceGreedyP(name string, shorthand string, value []*url.URL, usage string) *[]*url.URL {
+	return CommandLine.URLSliceGreedyP(name, shorthand, value, usage)
+}
@@ -0,0 +1,49 @@
+package greedyflag
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestNetFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		define   func(fs *FlagSet)
+		good     []string
+		want     string // Value.String() after parsing good
+		bad      []string
+		typeName string
+	}{
+		{"addr", func(fs *FlagSet) { fs.AddrP("f", "f", netip.Addr{}, "") }, []string{"-f", "10.0.0.1"}, "10.0.0.1", []string{"-f", "10.0.0.256"}, "ip"},
+		{"ipv6 addr", func(fs *FlagSet) { fs.AddrP("f", "f", netip.Addr{}, "") }, []string{"-f", "::1"}, "::1", []string{"-f", "::g"}, "ip"},
+		{"prefix", func(fs *FlagSet) { fs.PrefixP("f", "f", netip.Prefix{}, "") }, []string{"-f", "10.0.0.0/8"}, "10.0.0.0/8", []string{"-f", "10.0.0.0/33"}, "cidr"},
+		{"addr port", func(fs *FlagSet) { fs.AddrPortP("f", "f", netip.AddrPort{}, "") }, []string{"-f", "127.0.0.1:8080"}, "127.0.0.1:8080", []string{"-f", "127.0.0.1"}, "ip:port"},
+		{"url", func(fs *FlagSet) { fs.URLP("f", "f", nil, "") }, []string{"-f", "https://example.com/a?b=c"}, "https://example.com/a?b=c", []string{"-f", "://missing-scheme"}, "url"},
+		{"addr slice", func(fs *FlagSet) { fs.AddrSliceGreedyP("f", "f", nil, "") }, []string{"-f", "10.0.0.1", "::1"}, "[10.0.0.1,::1]", []string{"-f", "10.0.0.1", "host"}, "ip"},
+		{"prefix slice", func(fs *FlagSet) { fs.PrefixSliceGreedyP("f", "f", nil, "") }, []string{"-f", "10.0.0.0/8", "fd00::/8"}, "[10.0.0.0/8,fd00::/8]", []string{"-f", "10.0.0.1"}, "cidr"},
+		{"addr port slice", func(fs *FlagSet) { fs.AddrPortSliceGreedyP("f", "f", nil, "") }, []string{"-f", "127.0.0.1:80", "[::1]:443"}, "[127.0.0.1:80,[::1]:443]", []string{"-f", "127.0.0.1:http"}, "ip:port"},
+		{"url slice", func(fs *FlagSet) { fs.URLSliceGreedyP("f", "f", nil, "") }, []string{"-f", "http://a", "http://b/c"}, "[http://a,http://b/c]", []string{"-f", "http://a", "%zz"}, "url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("t")
+			tt.define(fs)
+			if err := fs.ParseArgs(tt.good); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.good, err)
+			}
+			if got := fs.Lookup("f").Value.String(); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+			if typeName, _ := flagType(fs.Lookup("f")); typeName != tt.typeName {
+				t.Errorf("help type name = %q, want %q", typeName, tt.typeName)
+			}
+
+			fs = NewFlagSet("t")
+			tt.define(fs)
+			if err := fs.ParseArgs(tt.bad); !errors.Is(err, ErrParsing) {
+				t.Errorf("ParseArgs(%q) error = %v, want ErrParsing", tt.bad, err)
+			}
+		})
+	}
+}
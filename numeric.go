@@ -0,0 +1,316 @@
+package greedyflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// --- Numeric Value Types ---
+
+// numError turns a strconv error into a short message naming the offending value.
+func numError(kind string, s string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%s value %q out of range", kind, s)
+	}
+	return fmt.Errorf("invalid %s value %q", kind, s)
+}
+
+// -- intValue --
+type intValue int
+
+func newIntValue(val int, p *int) *intValue {
+	*p = val
+	return (*intValue)(p)
+}
+func (i *intValue) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return numError("integer", s, err)
+	}
+	*i = intValue(v)
+	return nil
+}
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+// -- int64Value --
+type int64Value int64
+
+func newInt64Value(val int64, p *int64) *int64Value {
+	*p = val
+	return (*int64Value)(p)
+}
+func (i *int64Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return numError("integer", s, err)
+	}
+	*i = int64Value(v)
+	return nil
+}
+func (i *int64Value) String() string { return strconv.FormatInt(int64(*i), 10) }
+
+// -- uintValue --
+type uintValue uint
+
+func newUintValue(val uint, p *uint) *uintValue {
+	*p = val
+	return (*uintValue)(p)
+}
+func (i *uintValue) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+	if err != nil {
+		return numError("unsigned integer", s, err)
+	}
+	*i = uintValue(v)
+	return nil
+}
+func (i *uintValue) String() string { return strconv.FormatUint(uint64(*i), 10) }
+
+// -- uint64Value --
+type uint64Value uint64
+
+func newUint64Value(val uint64, p *uint64) *uint64Value {
+	*p = val
+	return (*uint64Value)(p)
+}
+func (i *uint64Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return numError("unsigned integer", s, err)
+	}
+	*i = uint64Value(v)
+	return nil
+}
+func (i *uint64Value) String() string { return strconv.FormatUint(uint64(*i), 10) }
+
+// -- float64Value --
+type float64Value float64
+
+func newFloat64Value(val float64, p *float64) *float64Value {
+	*p = val
+	return (*float64Value)(p)
+}
+func (f *float64Value) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return numError("floating-point", s, err)
+	}
+	*f = float64Value(v)
+	return nil
+}
+func (f *float64Value) String() string { return strconv.FormatFloat(float64(*f), 'g', -1, 64) }
+
+// -- countValue --
+type countValue int
+
+// countIncrement is passed to countValue.Set for each bare occurrence of a counter flag.
+const countIncrement = "+1"
+
+func newCountValue(val int, p *int) *countValue {
+	*p = val
+	return (*countValue)(p)
+}
+func (c *countValue) Set(s string) error {
+	if s == countIncrement {
+		*c++
+		return nil
+	}
+	// An explicit value (--verbose=3) sets the count directly
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return numError("count", s, err)
+	}
+	*c = countValue(v)
+	return nil
+}
+func (c *countValue) String() string { return strconv.Itoa(int(*c)) }
+
+// --- Numeric Flag Definition Functions ---
+
+// IntVarP defines an int flag with specified name, shorthand, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag.
+func (fs *FlagSet) IntVarP(p *int, name string, shorthand string, value int, usage string) {
+	v := newIntValue(value, p)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// IntVarP defines an int flag with specified name, shorthand, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag.
+func IntVarP(p *int, name string, shorthand string, value int, usage string) {
+	CommandLine.IntVarP(p, name, shorthand, value, usage)
+}
+
+// IntP is like IntVarP, but returns a pointer to an int variable.
+func (fs *FlagSet) IntP(name string, shorthand string, value int, usage string) *int {
+	p := new(int)
+	fs.IntVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// IntP is like IntVarP, but returns a pointer to an int variable.
+func IntP(name string, shorthand string, value int, usage string) *int {
+	return CommandLine.IntP(name, shorthand, value, usage)
+}
+
+// Int64VarP defines an int64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to an int64 variable in which to store the value of the flag.
+func (fs *FlagSet) Int64VarP(p *int64, name string, shorthand string, value int64, usage string) {
+	v := newInt64Value(value, p)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// Int64VarP defines an int64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to an int64 variable in which to store the value of the flag.
+func Int64VarP(p *int64, name string, shorthand string, value int64, usage string) {
+	CommandLine.Int64VarP(p, name, shorthand, value, usage)
+}
+
+// Int64P is like Int64VarP, but returns a pointer to an int64 variable.
+func (fs *FlagSet) Int64P(name string, shorthand string, value int64, usage string) *int64 {
+	p := new(int64)
+	fs.Int64VarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Int64P is like Int64VarP, but returns a pointer to an int64 variable.
+func Int64P(name string, shorthand string, value int64, usage string) *int64 {
+	return CommandLine.Int64P(name, shorthand, value, usage)
+}
+
+// UintVarP defines a uint flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a uint variable in which to store the value of the flag.
+func (fs *FlagSet) UintVarP(p *uint, name string, shorthand string, value uint, usage string) {
+	v := newUintValue(value, p)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// UintVarP defines a uint flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a uint variable in which to store the value of the flag.
+func UintVarP(p *uint, name string, shorthand string, value uint, usage string) {
+	CommandLine.UintVarP(p, name, shorthand, value, usage)
+}
+
+// UintP is like UintVarP, but returns a pointer to a uint variable.
+func (fs *FlagSet) UintP(name string, shorthand string, value uint, usage string) *uint {
+	p := new(uint)
+	fs.UintVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// UintP is like UintVarP, but returns a pointer to a uint variable.
+func UintP(name string, shorthand string, value uint, usage string) *uint {
+	return CommandLine.UintP(name, shorthand, value, usage)
+}
+
+// Uint64VarP defines a uint64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a uint64 variable in which to store the value of the flag.
+func (fs *FlagSet) Uint64VarP(p *uint64, name string, shorthand string, value uint64, usage string) {
+	v := newUint64Value(value, p)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// Uint64VarP defines a uint64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a uint64 variable in which to store the value of the flag.
+func Uint64VarP(p *uint64, name string, shorthand string, value uint64, usage string) {
+	CommandLine.Uint64VarP(p, name, shorthand, value, usage)
+}
+
+// Uint64P is like Uint64VarP, but returns a pointer to a uint64 variable.
+func (fs *FlagSet) Uint64P(name string, shorthand string, value uint64, usage string) *uint64 {
+	p := new(uint64)
+	fs.Uint64VarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Uint64P is like Uint64VarP, but returns a pointer to a uint64 variable.
+func Uint64P(name string, shorthand string, value uint64, usage string) *uint64 {
+	return CommandLine.Uint64P(name, shorthand, value, usage)
+}
+
+// Float64VarP defines a float64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a float64 variable in which to store the value of the flag.
+func (fs *FlagSet) Float64VarP(p *float64, name string, shorthand string, value float64, usage string) {
+	v := newFloat64Value(value, p)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// Float64VarP defines a float64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a float64 variable in which to store the value of the flag.
+func Float64VarP(p *float64, name string, shorthand string, value float64, usage string) {
+	CommandLine.Float64VarP(p, name, shorthand, value, usage)
+}
+
+// Float64P is like Float64VarP, but returns a pointer to a float64 variable.
+func (fs *FlagSet) Float64P(name string, shorthand string, value float64, usage string) *float64 {
+	p := new(float64)
+	fs.Float64VarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Float64P is like Float64VarP, but returns a pointer to a float64 variable.
+func Float64P(name string, shorthand string, value float64, usage string) *float64 {
+	return CommandLine.Float64P(name, shorthand, value, usage)
+}
+
+// CountVarP defines a counter flag with specified name, shorthand, and usage string.
+// Each occurrence increments the counter, so -vvv, -v -v -v and --verbose=3 all yield 3.
+// The argument p points to an int variable in which to store the count; it starts at 0.
+func (fs *FlagSet) CountVarP(p *int, name string, shorthand string, usage string) {
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     newCountValue(0, p),
+		DefValue:  "0",
+		IsCount:   true,
+	})
+}
+
+// CountVarP defines a counter flag with specified name, shorthand, and usage string.
+// The argument p points to an int variable in which to store the count.
+func CountVarP(p *int, name string, shorthand string, usage string) {
+	CommandLine.CountVarP(p, name, shorthand, usage)
+}
+
+// CountP is like CountVarP, but returns a pointer to an int variable.
+func (fs *FlagSet) CountP(name string, shorthand string, usage string) *int {
+	p := new(int)
+	fs.CountVarP(p, name, shorthand, usage)
+	return p
+}
+
+// CountP is like CountVarP, but returns a pointer to an int variable.
+func CountP(name string, shorthand string, usage string) *int {
+	return CommandLine.CountP(name, shorthand, usage)
+}
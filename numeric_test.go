@@ -0,0 +1,54 @@
+package greedyflag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCountFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"-v"}, 1},
+		{[]string{"-vvv"}, 3},
+		{[]string{"-v", "-v", "-v"}, 3},
+		{[]string{"--verbose", "-vv"}, 3},
+		{[]string{"--verbose=3"}, 3},
+		{[]string{"-v=2"}, 2},
+		{[]string{"-vv", "--verbose=5", "-v"}, 6},
+		{[]string{"-qvv"}, 2},
+	}
+	for _, tt := range tests {
+		fs := NewFlagSet("t")
+		verbose := fs.CountP("verbose", "v", "Increase verbosity")
+		fs.BoolP("quiet", "q", false, "Quiet")
+		if err := fs.ParseArgs(tt.args); err != nil {
+			t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+		}
+		if *verbose != tt.want {
+			t.Errorf("ParseArgs(%q): verbose = %d, want %d", tt.args, *verbose, tt.want)
+		}
+	}
+}
+
+func TestCountFlagErrors(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.CountP("verbose", "v", "Increase verbosity")
+	if err := fs.ParseArgs([]string{"--verbose=lots"}); !errors.Is(err, ErrParsing) {
+		t.Errorf("--verbose=lots error = %v, want ErrParsing", err)
+	}
+}
+
+func TestCountFlagHelp(t *testing.T) {
+	fs := NewFlagSet("t")
+	fs.CountP("verbose", "v", "Increase verbosity")
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	if !strings.Contains(out.String(), "Increase verbosity (repeatable)") {
+		t.Errorf("PrintDefaults output %q does not mark the counter as repeatable", out.String())
+	}
+}
@@ -0,0 +1,153 @@
+package greedyflag
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// --- Named Positional Arguments ---
+
+// positionalArg is a positional argument declared with PositionalVar.
+type positionalArg struct {
+	name     string
+	usage    string
+	value    Value
+	defValue string
+}
+
+// positionalValue wraps p in the Value type used for flags of the same type, so positional
+// values are converted and validated exactly like flag values.
+func positionalValue(p any) Value {
+	switch v := p.(type) {
+	case Value:
+		return v
+	case *string:
+		return newStringValue(*v, v)
+	case *bool:
+		return newBoolValue(*v, v)
+	case *int:
+		return newIntValue(*v, v)
+	case *int64:
+		return newInt64Value(*v, v)
+	case *uint:
+		return newUintValue(*v, v)
+	case *uint64:
+		return newUint64Value(*v, v)
+	case *float64:
+		return newFloat64Value(*v, v)
+	case *time.Duration:
+		return newDurationValue(*v, v)
+	case *time.Time:
+		return newTimeValue(*v, v, nil)
+	case *DateRange:
+		return newDateRangeValue(*v, v, nil)
+	case encoding.TextUnmarshaler:
+		return newTextValue(nil, v)
+	}
+	panic(fmt.Sprintf("greedyflag: unsupported positional argument type %T", p))
+}
+
+// PositionalVar declares a named positional argument with specified name and usage string.
+// The argument p points to a variable in which to store the value; its current value is the
+// default. p may be a *string, *bool, *int, *int64, *uint, *uint64, *float64, *time.Duration,
+// *time.Time, *DateRange, a pointer implementing encoding.TextUnmarshaler, or a Value.
+//
+// Positionals are bound in declaration order to Args() after Parse has validated their count,
+// and a value that cannot be converted is a parse error. If no positional mode is configured,
+// declaring positionals implies SetMandatoryNArgs with their number; otherwise the mode decides
+// how many are required and the rest keep their defaults. Usage and PrintDefaults show the names.
+func (fs *FlagSet) PositionalVar(p any, name string, usage string) {
+	for _, a := range fs.positionals {
+		if a.name == name {
+			panic(fmt.Sprintf("greedyflag: positional argument redefined: %s", name))
+		}
+	}
+	v := positionalValue(p)
+	fs.positionals = append(fs.positionals, &positionalArg{name: name, usage: usage, value: v, defValue: v.String()})
+}
+
+// PositionalVar declares a named positional argument of the default set with specified name and
+// usage string. See FlagSet.PositionalVar.
+func PositionalVar(p any, name string, usage string) {
+	CommandLine.PositionalVar(p, name, usage)
+}
+
+// effectivePositionals returns the positional mode and MandatoryN bounds in effect,
+// including the MandatoryN implied by declared positionals when no mode is configured.
+// It does not modify the set, so help output can be printed before Parse.
+func (fs *FlagSet) effectivePositionals() (mode positionalMode, lo int, hi int) {
+	if fs.posMode == modeNone && len(fs.positionals) > 0 {
+		return modeMandatoryN, len(fs.positionals), len(fs.positionals)
+	}
+	return fs.posMode, fs.mandatoryN, fs.mandatoryMax
+}
+
+// applyImpliedPositionals switches a set with declared positionals but no positional mode
+// to MandatoryN with their number.
+func (fs *FlagSet) applyImpliedPositionals() {
+	if fs.posMode == modeNone && len(fs.positionals) > 0 {
+		fs.posMode, fs.mandatoryN, fs.mandatoryMax = fs.effectivePositionals()
+		slog.Debug("Positional mode implied by declared positionals: Mandatory N", "set", fs.name, "N", fs.mandatoryN)
+	}
+}
+
+// bindPositionals sets the declared positionals from the validated positional arguments.
+func (fs *FlagSet) bindPositionals() error {
+	for i, a := range fs.positionals {
+		if i >= len(fs.args) {
+			break
+		}
+		if err := a.value.Set(fs.args[i]); err != nil {
+			return fmt.Errorf("%w: invalid value %q for argument <%s>: %v", ErrParsing, fs.args[i], a.name, err)
+		}
+		slog.Debug("Bound positional argument", "name", a.name, "value", fs.args[i])
+	}
+	return nil
+}
+
+// positionalName returns the display name of the i-th positional argument.
+func (fs *FlagSet) positionalName(i int) string {
+	if i < len(fs.positionals) {
+		return fs.positionals[i].name
+	}
+	return fmt.Sprintf("arg%d", i+1)
+}
+
+// declaredPositionalsDesc lists the declared positionals as optional usage terms, e.g. "[source] [dest] ".
+func (fs *FlagSet) declaredPositionalsDesc() string {
+	desc := ""
+	for _, a := range fs.positionals {
+		desc += "[" + a.name + "] "
+	}
+	return desc
+}
+
+// printPositionals prints the "Positional arguments" section of the help output.
+func (fs *FlagSet) printPositionals() {
+	if len(fs.positionals) == 0 {
+		return
+	}
+	mode, lo, _ := fs.effectivePositionals()
+	out := fs.Output()
+	fmt.Fprintf(out, "\nPositional arguments:\n")
+	for i, a := range fs.positionals {
+		typeName, _ := flagType(&Flag{Value: a.value})
+		line := "  " + a.name + " " + typeName
+		if len(line) < 24 {
+			line += strings.Repeat(" ", 24-len(line))
+		} else {
+			line += "\n    \t"
+		}
+		line += a.usage
+		// Only optional positionals (beyond the required count) can keep their default
+		if mode == modeMandatoryN && i < lo {
+			line += " (required)"
+		} else if a.defValue != "" && a.defValue != "false" && a.defValue != "0" {
+			line += fmt.Sprintf(" (default %s)", a.defValue)
+		}
+		fmt.Fprintln(out, line)
+	}
+}
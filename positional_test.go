@@ -0,0 +1,174 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPositionalUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		posConfig func(*FlagSet) error
+		want      string
+	}{
+		{"implied", nil, "Usage: cp <source> <dest> [flags]"},
+		{"interspersed", (*FlagSet).AllowInterspersedPositionals, "Usage: cp [source] [dest] [flags and args...]"},
+		{"trailing", (*FlagSet).AllowArbitraryTrailingPositionals, "Usage: cp [flags] [source] [dest] [args...]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("cp")
+			if tt.posConfig != nil {
+				if err := tt.posConfig(fs); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var src, dst string
+			fs.PositionalVar(&src, "source", "File to copy")
+			fs.PositionalVar(&dst, "dest", "Destination")
+			fs.BoolP("force", "f", false, "Overwrite")
+			var out strings.Builder
+			fs.SetOutput(&out)
+			fs.Usage()
+			if !strings.HasPrefix(out.String(), tt.want+"\n") {
+				t.Errorf("usage = %q, want first line %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPositionalUsageDoesNotChangeMode(t *testing.T) {
+	fs := NewFlagSet("cp")
+	fs.SetOutput(&strings.Builder{})
+	var src string
+	fs.PositionalVar(&src, "source", "File to copy")
+	fs.Usage()
+	// Declaring a positional mode after printing help must still be possible
+	if err := fs.AllowArbitraryTrailingPositionals(); err != nil {
+		t.Fatalf("AllowArbitraryTrailingPositionals after Usage: %v", err)
+	}
+	if err := fs.ParseArgs([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if src != "a" || !reflect.DeepEqual(fs.Args(), []string{"a", "b"}) {
+		t.Errorf("source = %q, Args() = %q; want a, [a b]", src, fs.Args())
+	}
+}
+
+func TestInterspersedPositionals(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		verbose    bool
+		extensions []string
+		positional []string
+	}{
+		{"between flags", []string{"a", "-v", "b"}, true, nil, []string{"a", "b"}},
+		{"lone dash is positional", []string{"-v", "-", "b"}, true, nil, []string{"-", "b"}},
+		{"tokens after -- are positional", []string{"a", "--", "-v", "--", "b"}, false, nil, []string{"a", "-v", "--", "b"}},
+		{"greedy flag ends at --", []string{"-e", "go", "py", "--", "a"}, false, []string{"go", "py"}, []string{"a"}},
+		{"none", []string{"-v"}, true, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, (*FlagSet).AllowInterspersedPositionals)
+			if err := f.fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if *f.verbose != tt.verbose || !reflect.DeepEqual(*f.extensions, orEmpty(tt.extensions)) || !reflect.DeepEqual(orEmpty(f.fs.Args()), tt.positional) {
+				t.Errorf("verbose = %v, extensions = %q, Args() = %q; want %v, %q, %q", *f.verbose, *f.extensions, f.fs.Args(), tt.verbose, tt.extensions, tt.positional)
+			}
+		})
+	}
+}
+
+func TestArbitraryTrailingPositionals(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		verbose    bool
+		positional []string
+	}{
+		{"first positional ends flag parsing", []string{"-v", "a", "-o", "b"}, true, []string{"a", "-o", "b"}},
+		{"-- before positionals", []string{"-v", "--", "-a", "b"}, true, []string{"-a", "b"}},
+		{"lone dash is positional", []string{"-", "-v"}, false, []string{"-", "-v"}},
+		{"only positionals", []string{"a", "b"}, false, []string{"a", "b"}},
+		{"none", []string{"-v"}, true, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, (*FlagSet).AllowArbitraryTrailingPositionals)
+			if err := f.fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if *f.verbose != tt.verbose || *f.output != "" || !reflect.DeepEqual(orEmpty(f.fs.Args()), tt.positional) {
+				t.Errorf("verbose = %v, output = %q, Args() = %q; want %v, \"\", %q", *f.verbose, *f.output, f.fs.Args(), tt.verbose, tt.positional)
+			}
+		})
+	}
+}
+
+func TestPositionalRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		lo, hi     int
+		args       []string
+		positional []string
+		wantMsg    string // Empty for success
+	}{
+		{"leading within range", 1, 3, []string{"a", "b", "-v"}, []string{"a", "b"}, ""},
+		{"trailing within range", 1, 3, []string{"-v", "a", "b", "c"}, []string{"a", "b", "c"}, ""},
+		{"no upper limit", 1, -1, []string{"-v", "a", "b", "c", "d"}, []string{"a", "b", "c", "d"}, ""},
+		{"optional none given", 0, 2, []string{"-v"}, []string{}, ""},
+		{"too few", 2, 3, []string{"-v", "a"}, nil, "expected 2 to 3 positional arguments before the first flag or after the flags, found 0 before and 1 after: [a]"},
+		{"too many", 1, 2, []string{"-v", "a", "b", "c"}, nil, "expected 1 to 2 positional arguments"},
+		{"below an open minimum", 2, -1, []string{"a"}, nil, "expected at least 2 positional arguments"},
+		{"split between both ends", 1, 3, []string{"a", "-v", "b"}, nil, "expected 1 to 3 positional arguments all before the first flag or all after the flags, found 1 before and 1 after: [b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSpecFlags(t, func(fs *FlagSet) error { return fs.SetPositionalRange(tt.lo, tt.hi) })
+			err := f.fs.ParseArgs(tt.args)
+			if tt.wantMsg != "" {
+				if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Fatalf("ParseArgs(%q) error = %v, want ErrValidation containing %q", tt.args, err, tt.wantMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if got := orEmpty(f.fs.Args()); !reflect.DeepEqual(got, tt.positional) {
+				t.Errorf("Args() = %q, want %q", got, tt.positional)
+			}
+		})
+	}
+}
+
+func TestPositionalRangeConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		lo, hi  int
+		wantMsg string
+	}{
+		{"negative minimum", -1, 2, "minimum number of positional args cannot be negative"},
+		{"maximum below minimum", 3, 2, "maximum number of positional args (2) is less than the minimum (3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewFlagSet("t").SetPositionalRange(tt.lo, tt.hi)
+			if !errors.Is(err, ErrConfiguration) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("SetPositionalRange(%d, %d) error = %v, want ErrConfiguration containing %q", tt.lo, tt.hi, err, tt.wantMsg)
+			}
+		})
+	}
+	fs := NewFlagSet("t")
+	if err := fs.AllowInterspersedPositionals(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.SetPositionalRange(1, 2); !errors.Is(err, ErrConfiguration) {
+		t.Errorf("SetPositionalRange after another mode error = %v, want ErrConfiguration", err)
+	}
+}
@@ -0,0 +1,69 @@
+package greedyflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRemainderFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		cmd     []string
+		verbose bool
+		exts    []string
+	}{
+		{"long", []string{"-v", "--cmd", "make", "-j8", "--keep-going"}, []string{"make", "-j8", "--keep-going"}, true, nil},
+		{"short", []string{"-r", "make", "-v"}, []string{"make", "-v"}, false, nil},
+		{"long attached value", []string{"--cmd=make", "-j8"}, []string{"make", "-j8"}, false, nil},
+		{"short attached value", []string{"-r=make", "-j8"}, []string{"make", "-j8"}, false, nil},
+		{"last in a short cluster", []string{"-vr", "make", "test"}, []string{"make", "test"}, true, nil},
+		{"-- taken verbatim", []string{"--cmd", "git", "log", "--", "file"}, []string{"git", "log", "--", "file"}, false, nil},
+		{"ends a greedy flag", []string{"-e", "go", "py", "--cmd", "ls"}, []string{"ls"}, false, []string{"go", "py"}},
+		{"nothing left", []string{"--cmd"}, []string{}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("run")
+			cmd := fs.RemainderP("cmd", "r", "Command to run")
+			verbose := fs.BoolP("verbose", "v", false, "Verbose")
+			exts := fs.StringSliceGreedyP("extensions", "e", nil, "Extensions")
+			if err := fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(*cmd, tt.cmd) || *verbose != tt.verbose || !reflect.DeepEqual(*exts, orEmpty(tt.exts)) {
+				t.Errorf("cmd = %q, verbose = %v, extensions = %q; want %q, %v, %q", *cmd, *verbose, *exts, tt.cmd, tt.verbose, tt.exts)
+			}
+			if !fs.Lookup("cmd").changed {
+				t.Error("remainder flag not marked as set")
+			}
+		})
+	}
+}
+
+func TestRemainderFlagRedefined(t *testing.T) {
+	expectPanic := func(t *testing.T, fn func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(r.(string), "only one remainder flag allowed per set") {
+				t.Errorf("recover() = %v, want a remainder flag panic", r)
+			}
+		}()
+		fn()
+	}
+
+	t.Run("same set", func(t *testing.T) {
+		fs := NewFlagSet("run")
+		fs.RemainderP("cmd", "", "Command")
+		expectPanic(t, func() { fs.RemainderP("exec", "", "Command") })
+	})
+	t.Run("inherited persistent flag", func(t *testing.T) {
+		root := NewCommand("mytool", "", nil)
+		root.PersistentFlags().RemainderP("cmd", "", "Command")
+		sub := NewCommand("run", "", func(*Command, []string) error { return nil })
+		sub.Flags().RemainderP("exec", "", "Command")
+		root.AddCommand(sub)
+		expectPanic(t, func() { root.ExecuteArgs([]string{"run"}) })
+	})
+}
@@ -0,0 +1,253 @@
+package greedyflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Typed Slice Value (Used for typed Greedy Flags) ---
+
+// sliceValue stores a []T, converting each consumed token with parse.
+type sliceValue[T any] struct {
+	p      *[]T
+	parse  func(string) (T, error) // Converts one token into an element
+	format func(T) string          // Renders one element for help output
+	// dashOK, if set, reports whether a token starting with '-' is a valid element
+	// (e.g. a negative duration) rather than a flag. Plain negative numbers are
+	// already recognised by isNumeric.
+	dashOK func(string) bool
+	typ    string // Element type name shown by PrintDefaults, if not a built-in type
+}
+
+func newSliceValue[T any](val []T, p *[]T, parse func(string) (T, error), format func(T) string) *sliceValue[T] {
+	// Ensure the pointer is initialized if the default value is nil
+	if val == nil {
+		val = []T{}
+	}
+	*p = val
+	return &sliceValue[T]{p: p, parse: parse, format: format}
+}
+func (s *sliceValue[T]) Set(val string) error {
+	// For greedy flags, Set converts and appends the argument.
+	v, err := s.parse(val)
+	if err != nil {
+		return err
+	}
+	*s.p = append(*s.p, v)
+	return nil
+}
+func (s *sliceValue[T]) String() string {
+	if s.p == nil || len(*s.p) == 0 {
+		return "[]"
+	}
+	parts := make([]string, len(*s.p))
+	for i, v := range *s.p {
+		parts[i] = s.format(v)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+func (s *sliceValue[T]) typeName() string { return s.typ }
+func (s *sliceValue[T]) reset()           { *s.p = []T{} }
+func (s *sliceValue[T]) acceptsDash(arg string) bool {
+	return s.dashOK != nil && s.dashOK(arg)
+}
+
+// dashAcceptor is implemented by values whose elements may legitimately start with '-'.
+type dashAcceptor interface {
+	acceptsDash(arg string) bool
+}
+
+// acceptsDashToken reports whether the greedy flag f can consume the dash-prefixed
+// token arg as a value instead of treating it as the start of another flag.
+func acceptsDashToken(f *Flag, arg string) bool {
+	d, ok := f.Value.(dashAcceptor)
+	return ok && d.acceptsDash(arg)
+}
+
+// addSliceFlag defines a greedy flag backed by a sliceValue. Internal use.
+func addSliceFlag[T any](fs *FlagSet, p *[]T, name string, shorthand string, value []T, usage string, parse func(string) (T, error), format func(T) string) *sliceValue[T] {
+	// Create a copy of the default value slice to avoid modification issues
+	defaultValueCopy := make([]T, len(value))
+	copy(defaultValueCopy, value)
+	// Store default value representation for help message
+	defValStr := newSliceValue(defaultValueCopy, new([]T), parse, format).String()
+
+	v := newSliceValue(defaultValueCopy, p, parse, format)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  defValStr,
+		IsGreedy:  true,
+	})
+	return v
+}
+
+// Element parsers reuse the scalar Value types so error messages stay consistent.
+
+func parseInt(s string) (int, error) {
+	var v int
+	err := newIntValue(0, &v).Set(s)
+	return v, err
+}
+
+func parseInt64(s string) (int64, error) {
+	var v int64
+	err := newInt64Value(0, &v).Set(s)
+	return v, err
+}
+
+func parseUint(s string) (uint, error) {
+	var v uint
+	err := newUintValue(0, &v).Set(s)
+	return v, err
+}
+
+func parseFloat64(s string) (float64, error) {
+	var v float64
+	err := newFloat64Value(0, &v).Set(s)
+	return v, err
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value %q", s)
+	}
+	return v, nil
+}
+
+func formatString(v string) string   { return v }
+func formatInt(v int) string         { return (*intValue)(&v).String() }
+func formatInt64(v int64) string     { return (*int64Value)(&v).String() }
+func formatUint(v uint) string       { return (*uintValue)(&v).String() }
+func formatFloat64(v float64) string { return (*float64Value)(&v).String() }
+
+// --- Typed Greedy Flag Definition Functions ---
+
+// IntSliceGreedyVarP defines a greedy []int flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []int variable in which to store the values of the flag.
+func (fs *FlagSet) IntSliceGreedyVarP(p *[]int, name string, shorthand string, value []int, usage string) {
+	addSliceFlag(fs, p, name, shorthand, value, usage, parseInt, formatInt)
+}
+
+// IntSliceGreedyVarP defines a greedy []int flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []int variable in which to store the values of the flag.
+func IntSliceGreedyVarP(p *[]int, name string, shorthand string, value []int, usage string) {
+	CommandLine.IntSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// IntSliceGreedyP is like IntSliceGreedyVarP, but returns a pointer to a []int variable.
+func (fs *FlagSet) IntSliceGreedyP(name string, shorthand string, value []int, usage string) *[]int {
+	p := new([]int)
+	fs.IntSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// IntSliceGreedyP is like IntSliceGreedyVarP, but returns a pointer to a []int variable.
+func IntSliceGreedyP(name string, shorthand string, value []int, usage string) *[]int {
+	return CommandLine.IntSliceGreedyP(name, shorthand, value, usage)
+}
+
+// Int64SliceGreedyVarP defines a greedy []int64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []int64 variable in which to store the values of the flag.
+func (fs *FlagSet) Int64SliceGreedyVarP(p *[]int64, name string, shorthand string, value []int64, usage string) {
+	addSliceFlag(fs, p, name, shorthand, value, usage, parseInt64, formatInt64)
+}
+
+// Int64SliceGreedyVarP defines a greedy []int64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []int64 variable in which to store the values of the flag.
+func Int64SliceGreedyVarP(p *[]int64, name string, shorthand string, value []int64, usage string) {
+	CommandLine.Int64SliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// Int64SliceGreedyP is like Int64SliceGreedyVarP, but returns a pointer to a []int64 variable.
+func (fs *FlagSet) Int64SliceGreedyP(name string, shorthand string, value []int64, usage string) *[]int64 {
+	p := new([]int64)
+	fs.Int64SliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Int64SliceGreedyP is like Int64SliceGreedyVarP, but returns a pointer to a []int64 variable.
+func Int64SliceGreedyP(name string, shorthand string, value []int64, usage string) *[]int64 {
+	return CommandLine.Int64SliceGreedyP(name, shorthand, value, usage)
+}
+
+// UintSliceGreedyVarP defines a greedy []uint flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []uint variable in which to store the values of the flag.
+func (fs *FlagSet) UintSliceGreedyVarP(p *[]uint, name string, shorthand string, value []uint, usage string) {
+	addSliceFlag(fs, p, name, shorthand, value, usage, parseUint, formatUint)
+}
+
+// UintSliceGreedyVarP defines a greedy []uint flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []uint variable in which to store the values of the flag.
+func UintSliceGreedyVarP(p *[]uint, name string, shorthand string, value []uint, usage string) {
+	CommandLine.UintSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// UintSliceGreedyP is like UintSliceGreedyVarP, but returns a pointer to a []uint variable.
+func (fs *FlagSet) UintSliceGreedyP(name string, shorthand string, value []uint, usage string) *[]uint {
+	p := new([]uint)
+	fs.UintSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// UintSliceGreedyP is like UintSliceGreedyVarP, but returns a pointer to a []uint variable.
+func UintSliceGreedyP(name string, shorthand string, value []uint, usage string) *[]uint {
+	return CommandLine.UintSliceGreedyP(name, shorthand, value, usage)
+}
+
+// Float64SliceGreedyVarP defines a greedy []float64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []float64 variable in which to store the values of the flag.
+func (fs *FlagSet) Float64SliceGreedyVarP(p *[]float64, name string, shorthand string, value []float64, usage string) {
+	addSliceFlag(fs, p, name, shorthand, value, usage, parseFloat64, formatFloat64)
+}
+
+// Float64SliceGreedyVarP defines a greedy []float64 flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []float64 variable in which to store the values of the flag.
+func Float64SliceGreedyVarP(p *[]float64, name string, shorthand string, value []float64, usage string) {
+	CommandLine.Float64SliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// Float64SliceGreedyP is like Float64SliceGreedyVarP, but returns a pointer to a []float64 variable.
+func (fs *FlagSet) Float64SliceGreedyP(name string, shorthand string, value []float64, usage string) *[]float64 {
+	p := new([]float64)
+	fs.Float64SliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Float64SliceGreedyP is like Float64SliceGreedyVarP, but returns a pointer to a []float64 variable.
+func Float64SliceGreedyP(name string, shorthand string, value []float64, usage string) *[]float64 {
+	return CommandLine.Float64SliceGreedyP(name, shorthand, value, usage)
+}
+
+// DurationSliceGreedyVarP defines a greedy []time.Duration flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the values of the flag.
+// Negative durations such as "-1h30m" are consumed as values rather than treated as flags.
+func (fs *FlagSet) DurationSliceGreedyVarP(p *[]time.Duration, name string, shorthand string, value []time.Duration, usage string) {
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parseDuration, time.Duration.String)
+	v.dashOK = func(arg string) bool {
+		_, err := time.ParseDuration(arg)
+		return err == nil
+	}
+}
+
+// DurationSliceGreedyVarP defines a greedy []time.Duration flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the values of the flag.
+func DurationSliceGreedyVarP(p *[]time.Duration, name string, shorthand string, value []time.Duration, usage string) {
+	CommandLine.DurationSliceGreedyVarP(p, name, shorthand, value, usage)
+}
+
+// DurationSliceGreedyP is like DurationSliceGreedyVarP, but returns a pointer to a []time.Duration variable.
+func (fs *FlagSet) DurationSliceGreedyP(name string, shorthand string, value []time.Duration, usage string) *[]time.Duration {
+	p := new([]time.Duration)
+	fs.DurationSliceGreedyVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// DurationSliceGreedyP is like DurationSliceGreedyVarP, but returns a pointer to a []time.Duration variable.
+func DurationSliceGreedyP(name string, shorthand string, value []time.Duration, usage string) *[]time.Duration {
+	return CommandLine.DurationSliceGreedyP(name, shorthand, value, usage)
+}
@@ -0,0 +1,84 @@
+package greedyflag
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// --- Value Source Provenance ---
+
+// SourceKind identifies where the value of a flag came from.
+type SourceKind int
+
+const (
+	// SourceDefault means the flag kept its default value.
+	SourceDefault SourceKind = iota
+	// SourceEnvironment means the value was read from an environment variable.
+	SourceEnvironment
+	// SourceConfigFile means the value was read from a config file.
+	SourceConfigFile
+	// SourceCommandLine means the flag was given on the command line.
+	SourceCommandLine
+)
+
+// String returns a short name for the source kind, e.g. "env".
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceEnvironment:
+		return "env"
+	case SourceConfigFile:
+		return "config"
+	case SourceCommandLine:
+		return "command line"
+	}
+	return fmt.Sprintf("SourceKind(%d)", int(k))
+}
+
+// ValueSource records where the value of a flag came from. Only the fields for its Kind are set.
+//
+// ArgIndex is an index in os.Args after Parse or Command.Execute, and in the slice passed to
+// ParseArgs or Command.ExecuteArgs otherwise. Subcommand names count: for "mytool serve -v",
+// Execute reports -v at index 2.
+type ValueSource struct {
+	Kind     SourceKind
+	EnvVar   string // Environment variable name (SourceEnvironment)
+	File     string // Config file path (SourceConfigFile)
+	Line     int    // 1-based line in File (SourceConfigFile; 0 if unknown)
+	ArgIndex int    // Index of the flag token on the command line (SourceCommandLine)
+}
+
+// String describes the source, e.g. "env $MYTOOL_OUTPUT" or "config app.toml:3".
+func (s ValueSource) String() string {
+	switch s.Kind {
+	case SourceEnvironment:
+		return "env $" + s.EnvVar
+	case SourceConfigFile:
+		if s.Line > 0 {
+			return fmt.Sprintf("config %s:%d", s.File, s.Line)
+		}
+		return "config " + s.File
+	case SourceCommandLine:
+		return fmt.Sprintf("command line (arg %d)", s.ArgIndex)
+	}
+	return s.Kind.String()
+}
+
+// Dump writes a table of every flag of the set with its final value and the source of that
+// value, for debugging questions like "why is output=/tmp?". Call it after Parse.
+func (fs *FlagSet) Dump(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tVALUE\tSOURCE")
+	fs.VisitAll(func(f *Flag) {
+		fmt.Fprintf(tw, "--%s\t%s\t%s\n", f.Name, f.Value.String(), f.Source)
+	})
+	tw.Flush()
+}
+
+// Dump writes a table of every flag of the default set with its final value and source.
+// See FlagSet.Dump.
+func Dump(w io.Writer) {
+	CommandLine.Dump(w)
+}
@@ -0,0 +1,70 @@
+package greedyflag
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValueSourceArgIndex(t *testing.T) {
+	newRoot := func() (*Command, *FlagSet) {
+		root := NewCommand("mytool", "", nil)
+		root.PersistentFlags().BoolP("verbose", "v", false, "Verbose")
+		serve := NewCommand("serve", "Serve", func(*Command, []string) error { return nil })
+		serve.Flags().StringP("out", "o", "", "Output")
+		root.AddCommand(serve)
+		return root, serve.Flags()
+	}
+
+	// ExecuteArgs counts the subcommand names it consumed
+	root, fs := newRoot()
+	if err := root.ExecuteArgs([]string{"serve", "-v", "--out", "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, o := fs.Lookup("verbose").Source.ArgIndex, fs.Lookup("out").Source.ArgIndex; v != 1 || o != 2 {
+		t.Errorf("ExecuteArgs indexes = %d, %d; want 1, 2", v, o)
+	}
+
+	// Execute and Parse index into os.Args
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+	os.Args = []string{"mytool", "serve", "-v"}
+	root, fs = newRoot()
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fs.Lookup("verbose").Source.ArgIndex; got != 2 {
+		t.Errorf("Execute index = %d, want 2", got)
+	}
+
+	os.Args = []string{"mytool", "pos", "-v"}
+	set := NewFlagSet("mytool")
+	if err := set.AllowArbitraryLeadingPositionals(); err != nil {
+		t.Fatal(err)
+	}
+	set.BoolP("verbose", "v", false, "Verbose")
+	if err := set.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if got := set.Lookup("verbose").Source; got.ArgIndex != 2 || got.String() != "command line (arg 2)" {
+		t.Errorf("Parse source = %v, want command line (arg 2)", got)
+	}
+}
+
+func TestDump(t *testing.T) {
+	t.Setenv("MYTOOL_OUT", "/tmp/env")
+	fs := NewFlagSet("mytool")
+	fs.SetEnvPrefix("MYTOOL")
+	fs.StringP("out", "o", "", "Output")
+	fs.BoolP("verbose", "v", false, "Verbose")
+	if err := fs.ParseArgs([]string{"-v"}); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	fs.Dump(&out)
+	for _, want := range []string{"--out      /tmp/env  env $MYTOOL_OUT", "--verbose  true      command line (arg 0)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Dump output %q does not contain %q", out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package greedyflag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newExecSet returns a set with a greedy --exec flag that ends at ";".
+func newExecSet(t *testing.T, arity ...int) (*FlagSet, *[]string, *bool) {
+	t.Helper()
+	fs := NewFlagSet("find")
+	if err := fs.AllowArbitraryTrailingPositionals(); err != nil {
+		t.Fatal(err)
+	}
+	exec := fs.StringSliceGreedyP("exec", "x", nil, "Command to run")
+	verbose := fs.BoolP("verbose", "v", false, "Verbose")
+	if err := fs.SetGreedyTerminator("exec", ";"); err != nil {
+		t.Fatal(err)
+	}
+	if len(arity) == 2 {
+		if err := fs.SetGreedyArity("exec", arity[0], arity[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fs, exec, verbose
+}
+
+func TestGreedyTerminator(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		exec       []string
+		verbose    bool
+		positional []string
+	}{
+		{"dash tokens and -- are consumed", []string{"--exec", "grep", "-v", "--", "x", ";", "-v", "dir"}, []string{"grep", "-v", "--", "x"}, true, []string{"dir"}},
+		{"short flag", []string{"-x", "rm", "{}", ";"}, []string{"rm", "{}"}, false, []string{}},
+		{"long attached value", []string{"--exec=rm", "-f", ";", "dir"}, []string{"rm", "-f"}, false, []string{"dir"}},
+		{"short attached value", []string{"-x=rm", "-f", ";"}, []string{"rm", "-f"}, false, []string{}},
+		{"repeated occurrences", []string{"-x", "a", ";", "-x", "b", ";"}, []string{"a", "b"}, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, exec, verbose := newExecSet(t)
+			if err := fs.ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(*exec, tt.exec) || *verbose != tt.verbose || !reflect.DeepEqual(orEmpty(fs.Args()), tt.positional) {
+				t.Errorf("exec = %q, verbose = %v, Args() = %q; want %q, %v, %q", *exec, *verbose, fs.Args(), tt.exec, tt.verbose, tt.positional)
+			}
+		})
+	}
+}
+
+func TestGreedyTerminatorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		arity   []int
+		wantMsg string
+	}{
+		{"missing terminator", []string{"--exec", "rm", "-f"}, nil, `flag --exec: missing terminator ";"`},
+		{"missing terminator after attached value", []string{"--exec=rm"}, nil, "missing terminator"},
+		{"too many values", []string{"--exec", "a", "b", "c", ";"}, []int{1, 2}, "flag --exec expects 1-2 values, got 3"},
+		{"too few values", []string{"--exec", ";"}, []int{1, 0}, "flag --exec expects at least 1 value(s), got 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _, _ := newExecSet(t, tt.arity...)
+			err := fs.ParseArgs(tt.args)
+			if !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ParseArgs(%q) error = %v, want ErrParsing containing %q", tt.args, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestGreedyTerminatorHelp(t *testing.T) {
+	fs, _, _ := newExecSet(t)
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	if !strings.Contains(out.String(), `(ends at ";")`) {
+		t.Errorf("PrintDefaults output %q does not mention the terminator", out.String())
+	}
+}
@@ -0,0 +1,102 @@
+package greedyflag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// --- encoding.TextUnmarshaler Values ---
+
+// -- textValue --
+type textValue struct {
+	p   encoding.TextUnmarshaler
+	typ string // Type name shown by PrintDefaults
+}
+
+func newTextValue(val encoding.TextMarshaler, p encoding.TextUnmarshaler) *textValue {
+	ptrVal := reflect.ValueOf(p)
+	if ptrVal.Kind() != reflect.Pointer || ptrVal.IsNil() {
+		panic("greedyflag: text flag variable must be a non-nil pointer")
+	}
+	if val != nil {
+		defVal := reflect.ValueOf(val)
+		if defVal.Kind() == reflect.Pointer {
+			defVal = defVal.Elem()
+		}
+		if defVal.Type() != ptrVal.Type().Elem() {
+			panic(fmt.Sprintf("greedyflag: default type does not match variable type: %v != %v", defVal.Type(), ptrVal.Type().Elem()))
+		}
+		ptrVal.Elem().Set(defVal)
+	}
+	return &textValue{p: p, typ: typeNameOfType(ptrVal.Type().Elem())}
+}
+func (v *textValue) Set(s string) error {
+	return v.p.UnmarshalText([]byte(s))
+}
+func (v *textValue) String() string {
+	if m, ok := v.p.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return ""
+}
+func (v *textValue) typeName() string { return v.typ }
+
+// marshalText returns the text form of val, or "" if val is nil or cannot be marshaled.
+func marshalText(val encoding.TextMarshaler) string {
+	if val == nil {
+		return ""
+	}
+	if rv := reflect.ValueOf(val); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return ""
+	}
+	b, err := val.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// --- Text Flag Definition Functions ---
+
+// TextVarP defines a flag with specified name, shorthand, default value, and usage string.
+// The argument p must be a pointer to a variable that will hold the value of the flag,
+// and p must implement encoding.TextUnmarshaler. If the flag is used, the flag value will
+// be passed to p's UnmarshalText method. The type of the default value must be the same
+// as the type of p; its MarshalText form is shown as the default in help output.
+func (fs *FlagSet) TextVarP(p encoding.TextUnmarshaler, name string, shorthand string, value encoding.TextMarshaler, usage string) {
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     newTextValue(value, p),
+		DefValue:  marshalText(value),
+	})
+}
+
+// TextVarP defines a flag with specified name, shorthand, default value, and usage string.
+// The argument p must be a pointer to a variable implementing encoding.TextUnmarshaler.
+// See FlagSet.TextVarP.
+func TextVarP(p encoding.TextUnmarshaler, name string, shorthand string, value encoding.TextMarshaler, usage string) {
+	CommandLine.TextVarP(p, name, shorthand, value, usage)
+}
+
+// TextSliceGreedyVar defines a greedy []T flag in fs with specified name, shorthand, default
+// value, and usage string, where *T implements encoding.TextUnmarshaler. Each consumed token
+// is decoded into a fresh element with UnmarshalText and appended.
+// The argument p points to a []T variable in which to store the values of the flag.
+// Use CommandLine as fs for the default set.
+func TextSliceGreedyVar[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](fs *FlagSet, p *[]T, name string, shorthand string, value []T, usage string) {
+	parse := func(s string) (T, error) {
+		var v T
+		err := PT(&v).UnmarshalText([]byte(s))
+		return v, err
+	}
+	v := addSliceFlag(fs, p, name, shorthand, value, usage, parse, formatValue[T])
+	v.typ = typeNameOf[T]()
+}
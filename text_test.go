@@ -0,0 +1,48 @@
+package greedyflag
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestTextFlags(t *testing.T) {
+	newSet := func() (*FlagSet, *big.Int, *[]big.Int) {
+		fs := NewFlagSet("t")
+		n := new(big.Int)
+		fs.TextVarP(n, "big", "b", big.NewInt(7), "A big number")
+		var ns []big.Int
+		TextSliceGreedyVar(fs, &ns, "bigs", "n", nil, "Big numbers")
+		return fs, n, &ns
+	}
+
+	fs, n, ns := newSet()
+	if n.String() != "7" || fs.Lookup("big").DefValue != "7" {
+		t.Errorf("default = %s (DefValue %q), want 7", n, fs.Lookup("big").DefValue)
+	}
+	if err := fs.ParseArgs([]string{"-b", "123456789012345678901234567890", "-n", "1", "-2", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if n.String() != "123456789012345678901234567890" {
+		t.Errorf("big = %s", n)
+	}
+	if got := fs.Lookup("bigs").Value.String(); got != "[1,-2,3]" {
+		t.Errorf("bigs = %s, want [1,-2,3]", got)
+	}
+	if len(*ns) != 3 {
+		t.Errorf("len(bigs) = %d, want 3", len(*ns))
+	}
+	for _, name := range []string{"big", "bigs"} {
+		if typeName, _ := flagType(fs.Lookup(name)); typeName != "int" {
+			t.Errorf("help type name of --%s = %q, want \"int\"", name, typeName)
+		}
+	}
+
+	for _, args := range [][]string{{"-b", "seven"}, {"-n", "1", "two"}} {
+		fs, _, _ := newSet()
+		if err := fs.ParseArgs(args); !errors.Is(err, ErrParsing) || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("ParseArgs(%q) error = %v, want ErrParsing", args, err)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package greedyflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultTimeLayouts are used by time and date-range flags defined without explicit layouts.
+var defaultTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", time.DateOnly}
+
+// --- Time Value Types ---
+
+// -- durationValue --
+type durationValue time.Duration
+
+func newDurationValue(val time.Duration, p *time.Duration) *durationValue {
+	*p = val
+	return (*durationValue)(p)
+}
+func (d *durationValue) Set(s string) error {
+	v, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = durationValue(v)
+	return nil
+}
+func (d *durationValue) String() string { return time.Duration(*d).String() }
+
+// acceptsDash lets "--since -2h" take a negative duration as its value.
+func (d *durationValue) acceptsDash(arg string) bool {
+	_, err := time.ParseDuration(arg)
+	return err == nil
+}
+
+// -- timeValue --
+type timeValue struct {
+	p       *time.Time
+	layouts []string // Accepted layouts, tried in order; the first is used for output
+}
+
+func newTimeValue(val time.Time, p *time.Time, layouts []string) *timeValue {
+	*p = val
+	return &timeValue{p: p, layouts: timeLayouts(layouts)}
+}
+func (t *timeValue) Set(s string) error {
+	v, err := parseTime(s, t.layouts)
+	if err != nil {
+		return err
+	}
+	*t.p = v
+	return nil
+}
+func (t *timeValue) String() string { return formatTime(*t.p, t.layouts) }
+
+// DateRange is an interval between two points in time, written on the command
+// line as "START..END" (e.g. "2026-01-01..2026-02-01").
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// String returns the range in "START..END" form using the first default layout.
+func (r DateRange) String() string {
+	return formatDateRange(r, defaultTimeLayouts)
+}
+
+// -- dateRangeValue --
+type dateRangeValue struct {
+	p       *DateRange
+	layouts []string // Accepted layouts for both ends of the range
+}
+
+func newDateRangeValue(val DateRange, p *DateRange, layouts []string) *dateRangeValue {
+	*p = val
+	return &dateRangeValue{p: p, layouts: timeLayouts(layouts)}
+}
+func (r *dateRangeValue) Set(s string) error {
+	v, err := parseDateRange(s, r.layouts)
+	if err != nil {
+		return err
+	}
+	*r.p = v
+	return nil
+}
+func (r *dateRangeValue) String() string { return formatDateRange(*r.p, r.layouts) }
+
+// timeLayouts returns layouts, or the default layouts if none were given.
+func timeLayouts(layouts []string) []string {
+	if len(layouts) == 0 {
+		return defaultTimeLayouts
+	}
+	return append([]string(nil), layouts...)
+}
+
+// parseTime parses s with the first matching layout.
+func parseTime(s string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if v, err := time.Parse(layout, s); err == nil {
+			return v, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time value %q (expected layout %s)", s, strings.Join(layouts, " or "))
+}
+
+// formatTime formats t with the first layout; the zero time is rendered as "".
+func formatTime(t time.Time, layouts []string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layouts[0])
+}
+
+// parseDateRange parses "START..END", requiring END not to be before START.
+func parseDateRange(s string, layouts []string) (DateRange, error) {
+	startStr, endStr, found := strings.Cut(s, "..")
+	if !found || startStr == "" || endStr == "" {
+		return DateRange{}, fmt.Errorf("invalid date range %q (expected START..END)", s)
+	}
+	start, err := parseTime(startStr, layouts)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid start of date range %q: %w", s, err)
+	}
+	end, err := parseTime(endStr, layouts)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid end of date range %q: %w", s, err)
+	}
+	if end.Before(start) {
+		return DateRange{}, fmt.Errorf("invalid date range %q: end is before start", s)
+	}
+	return DateRange{Start: start, End: end}, nil
+}
+
+// formatDateRange formats r as "START..END"; the zero range is rendered as "".
+func formatDateRange(r DateRange, layouts []string) string {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return ""
+	}
+	return r.Start.Format(layouts[0]) + ".." + r.End.Format(layouts[0])
+}
+
+// --- Time Flag Definition Functions ---
+
+// DurationVarP defines a time.Duration flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a time.Duration variable in which to store the value of the flag.
+// The flag accepts any value understood by time.ParseDuration, including negative durations.
+func (fs *FlagSet) DurationVarP(p *time.Duration, name string, shorthand string, value time.Duration, usage string) {
+	v := newDurationValue(value, p)
+	defValStr := ""
+	if value != 0 {
+		defValStr = v.String()
+	}
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  defValStr,
+	})
+}
+
+// DurationVarP defines a time.Duration flag with specified name, shorthand, default value, and usage string.
+// The argument p points to a time.Duration variable in which to store the value of the flag.
+func DurationVarP(p *time.Duration, name string, shorthand string, value time.Duration, usage string) {
+	CommandLine.DurationVarP(p, name, shorthand, value, usage)
+}
+
+// DurationP is like DurationVarP, but returns a pointer to a time.Duration variable.
+func (fs *FlagSet) DurationP(name string, shorthand string, value time.Duration, usage string) *time.Duration {
+	p := new(time.Duration)
+	fs.DurationVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// DurationP is like DurationVarP, but returns a pointer to a time.Duration variable.
+func DurationP(name string, shorthand string, value time.Duration, usage string) *time.Duration {
+	return CommandLine.DurationP(name, shorthand, value, usage)
+}
+
+// TimeVarP defines a time.Time flag with specified name, shorthand, default value, accepted layouts, and usage string.
+// Values are parsed with the first matching layout in layouts (see time.Parse); if layouts is empty,
+// RFC 3339, "2006-01-02T15:04:05" and "2006-01-02" are accepted.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+func (fs *FlagSet) TimeVarP(p *time.Time, name string, shorthand string, value time.Time, layouts []string, usage string) {
+	v := newTimeValue(value, p, layouts)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// TimeVarP defines a time.Time flag with specified name, shorthand, default value, accepted layouts, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+func TimeVarP(p *time.Time, name string, shorthand string, value time.Time, layouts []string, usage string) {
+	CommandLine.TimeVarP(p, name, shorthand, value, layouts, usage)
+}
+
+// TimeP is like TimeVarP, but returns a pointer to a time.Time variable.
+func (fs *FlagSet) TimeP(name string, shorthand string, value time.Time, layouts []string, usage string) *time.Time {
+	p := new(time.Time)
+	fs.TimeVarP(p, name, shorthand, value, layouts, usage)
+	return p
+}
+
+// TimeP is like TimeVarP, but returns a pointer to a time.Time variable.
+func TimeP(name string, shorthand string, value time.Time, layouts []string, usage string) *time.Time {
+	return CommandLine.TimeP(name, shorthand, value, layouts, usage)
+}
+
+// DateRangeVarP defines a DateRange flag with specified name, shorthand, default value, accepted layouts, and usage string.
+// Values are written as "START..END"; both ends are parsed as in TimeVarP and END must not be before START.
+// The argument p points to a DateRange variable in which to store the value of the flag.
+func (fs *FlagSet) DateRangeVarP(p *DateRange, name string, shorthand string, value DateRange, layouts []string, usage string) {
+	v := newDateRangeValue(value, p, layouts)
+	fs.addFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     v,
+		DefValue:  v.String(),
+	})
+}
+
+// DateRangeVarP defines a DateRange flag with specified name, shorthand, default value, accepted layouts, and usage string.
+// The argument p points to a DateRange variable in which to store the value of the flag.
+func DateRangeVarP(p *DateRange, name string, shorthand string, value DateRange, layouts []string, usage string) {
+	CommandLine.DateRangeVarP(p, name, shorthand, value, layouts, usage)
+}
+
+// DateRangeP is like DateRangeVarP, but returns a pointer to a DateRange variable.
+func (fs *FlagSet) DateRangeP(name string, shorthand string, value DateRange, layouts []string, usage string) *DateRange {
+	p := new(DateRange)
+	fs.DateRangeVarP(p, name, shorthand, value, layouts, usage)
+	return p
+}
+
+// DateRangeP is like DateRangeVarP, but returns a pointer to a DateRange variable.
+func DateRangeP(name string, shorthand string, value DateRange, layouts []string, usage string) *DateRange {
+	return CommandLine.DateRangeP(name, shorthand, value, layouts, usage)
+}
+
+// TimeSliceGreedyVarP defines a greedy []time.Time flag with specified name, shorthand, default value, accepted layouts,
+// and usage string. Each consumed token is parsed as in TimeVarP.
+// The argument p points to a []time.Time variable in which to store the values of the flag.
+func (fs *FlagSet) TimeSliceGreedyVarP(p *[]time.Time, name string, shorthand string, value []time.Time, layouts []string, usage string) {
+	layouts = timeLayouts(layouts)
+	parse := func(s string) (time.Time, error) { return parseTime(s, layouts) }
+	format := func(t time.Time) string { return formatTime(t, layouts) }
+	addSliceFlag(fs, p, name, shorthand, value, usage, parse, format)
+}
+
+// TimeSliceGreedyVarP defines a greedy []time.Time flag with specified name, shorthand, default value, accepted layouts,
+// and usage string. The argument p points to a []time.Time variable in which to store the values of the flag.
+func TimeSliceGreedyVarP(p *[]time.Time, name string, shorthand string, value []time.Time, layouts []string, usage string) {
+	CommandLine.TimeSliceGreedyVarP(p, name, shorthand, value, layouts, usage)
+}
+
+// TimeSliceGreedyP is like TimeSliceGreedyVarP, but returns a pointer to a []time.Time variable.
+func (fs *FlagSet) TimeSliceGreedyP(name string, shorthand string, value []time.Time, layouts []string, usage string) *[]time.Time {
+	p := new([]time.Time)
+	fs.TimeSliceGreedyVarP(p, name, shorthand, value, layouts, usage)
+	return p
+}
+
+// TimeSliceGreedyP is like TimeSliceGreedyVarP, but returns a pointer to a []time.Time variable.
+func TimeSliceGreedyP(name string, shorthand string, value []time.Time, layouts []string, usage string) *[]time.Time {
+	return CommandLine.TimeSliceGreedyP(name, shorthand, value, layouts, usage)
+}
+
+// DateRangeSliceGreedyVarP defines a greedy []DateRange flag with specified name, shorthand, default value, accepted
+// layouts, and usage string. Each consumed token is parsed as in DateRangeVarP.
+// The argument p points to a []DateRange variable in which to store the values of the flag.
+func (fs *FlagSet) DateRangeSliceGreedyVarP(p *[]DateRange, name string, shorthand string, value []DateRange, layouts []string, usage string) {
+	layouts = timeLayouts(layouts)
+	parse := func(s string) (DateRange, error) { return parseDateRange(s, layouts) }
+	format := func(r DateRange) string { return formatDateRange(r, layouts) }
+	addSliceFlag(fs, p, name, shorthand, value, usage, parse, format)
+}
+
+// DateRangeSliceGreedyVarP defines a greedy []DateRange flag with specified name, shorthand, default value, accepted
+// layouts, and usage string. The argument p points to a []DateRange variable in which to store the values of the flag.
+func DateRangeSliceGreedyVarP(p *[]DateRange, name string, shorthand string, value []DateRange, layouts []string, usage string) {
+	CommandLine.DateRangeSliceGreedyVarP(p, name, shorthand, value, layouts, usage)
+}
+
+// DateRangeSliceGreedyP is like DateRangeSliceGreedyVarP, but returns a pointer to a []DateRange variable.
+func (fs *FlagSet) DateRangeSliceGreedyP(name string, shorthand string, value []DateRange, layouts []string, usage string) *[]DateRange {
+	p := new([]DateRange)
+	fs.DateRangeSliceGreedyVarP(p, name, shorthand, value, layouts, usage)
+	return p
+}
+
+// DateRangeSliceGreedyP is like DateRangeSliceGreedyVarP, but returns a pointer to a []DateRange variable.
+func DateRangeSliceGreedyP(name string, shorthand string, value []DateRange, layouts []string, usage string) *[]DateRange {
+	return CommandLine.DateRangeSliceGreedyP(name, shorthand, value, layouts, usage)
+}
@@ -0,0 +1,49 @@
+package greedyflag
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		define   func(fs *FlagSet)
+		good     []string
+		want     string // Value.String() after parsing good
+		bad      []string
+		typeName string
+	}{
+		{"duration", func(fs *FlagSet) { fs.DurationP("f", "f", time.Second, "") }, []string{"-f", "1m30s"}, "1m30s", []string{"-f", "90"}, "duration"},
+		{"negative duration", func(fs *FlagSet) { fs.DurationP("f", "f", 0, "") }, []string{"-f", "-5s"}, "-5s", []string{"-f", "-5"}, "duration"},
+		{"time", func(fs *FlagSet) { fs.TimeP("f", "f", time.Time{}, nil, "") }, []string{"-f", "2024-03-01"}, "2024-03-01T00:00:00Z", []string{"-f", "01/03/2024"}, "time"},
+		{"time with layouts", func(fs *FlagSet) { fs.TimeP("f", "f", time.Time{}, []string{"02.01.2006"}, "") }, []string{"-f", "01.03.2024"}, "01.03.2024", []string{"-f", "2024-03-01"}, "time"},
+		{"date range", func(fs *FlagSet) { fs.DateRangeP("f", "f", DateRange{}, []string{"2006-01-02"}, "") }, []string{"-f", "2024-01-01..2024-01-31"}, "2024-01-01..2024-01-31", []string{"-f", "2024-01-31..2024-01-01"}, "dateRange"},
+		{"date range without separator", func(fs *FlagSet) { fs.DateRangeP("f", "f", DateRange{}, nil, "") }, []string{"-f", "2024-01-01..2024-01-02"}, "2024-01-01T00:00:00Z..2024-01-02T00:00:00Z", []string{"-f", "2024-01-01"}, "dateRange"},
+		{"duration slice", func(fs *FlagSet) { fs.DurationSliceGreedyP("f", "f", nil, "") }, []string{"-f", "1s", "-2m"}, "[1s,-2m0s]", []string{"-f", "1s", "soon"}, "duration"},
+		{"time slice", func(fs *FlagSet) { fs.TimeSliceGreedyP("f", "f", nil, []string{"2006-01-02"}, "") }, []string{"-f", "2024-01-01", "2024-02-01"}, "[2024-01-01,2024-02-01]", []string{"-f", "2024-13-01"}, "time"},
+		{"date range slice", func(fs *FlagSet) { fs.DateRangeSliceGreedyP("f", "f", nil, []string{"2006-01-02"}, "") }, []string{"-f", "2024-01-01..2024-01-02", "2024-02-01..2024-02-02"}, "[2024-01-01..2024-01-02,2024-02-01..2024-02-02]", []string{"-f", "2024-01-01.."}, "dateRange"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("t")
+			tt.define(fs)
+			if err := fs.ParseArgs(tt.good); err != nil {
+				t.Fatalf("ParseArgs(%q): %v", tt.good, err)
+			}
+			if got := fs.Lookup("f").Value.String(); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+			if typeName, _ := flagType(fs.Lookup("f")); typeName != tt.typeName {
+				t.Errorf("help type name = %q, want %q", typeName, tt.typeName)
+			}
+
+			fs = NewFlagSet("t")
+			tt.define(fs)
+			if err := fs.ParseArgs(tt.bad); !errors.Is(err, ErrParsing) {
+				t.Errorf("ParseArgs(%q) error = %v, want ErrParsing", tt.bad, err)
+			}
+		})
+	}
+}